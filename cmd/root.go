@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/treethought/ethscan/ui"
+	"github.com/treethought/ethscan/util"
 
 	"github.com/spf13/viper"
 )
@@ -19,6 +20,7 @@ var rootCmd = &cobra.Command{
 	Use:   "ethscan",
 	Short: "Ethereum block explorer for the terminal",
 	Run: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(config.Validate())
 		app := ui.NewApp(config)
 		app.Init()
 
@@ -59,6 +61,9 @@ func initConfig() {
 	}
 
 	viper.AutomaticEnv() // read in environment variables that match
+	viper.SetDefault("blocks.newest_first", true)
+	viper.SetDefault("blocks.backfill", 20)
+	viper.SetDefault("blocks.max_rows", 500)
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
@@ -69,6 +74,62 @@ func initConfig() {
 		RpcUrl:       viper.GetString("rpc_url"),
 		EtherscanKey: viper.GetString("etherscan_key"),
 		DisableENS:   viper.GetBool("disable_ens"),
+		Keymap:       make(map[string][]string),
+		Theme:        viper.GetString("theme"),
+		Themes:       make(map[string]map[string]string),
+		Chain:        viper.GetString("chain"),
+		Chains:       make(map[string]util.Chain),
+		Blocks: ui.BlocksConfig{
+			Columns:     viper.GetStringSlice("blocks.columns"),
+			NewestFirst: viper.GetBool("blocks.newest_first"),
+			Backfill:    viper.GetInt("blocks.backfill"),
+			MaxRows:     viper.GetInt("blocks.max_rows"),
+		},
+	}
+	// actions map to a single key or a list of keys
+	for action, keys := range viper.GetStringMap("keymap") {
+		switch keys := keys.(type) {
+		case []interface{}:
+			for _, k := range keys {
+				conf.Keymap[action] = append(conf.Keymap[action], fmt.Sprint(k))
+			}
+		default:
+			conf.Keymap[action] = []string{fmt.Sprint(keys)}
+		}
+	}
+
+	for name := range viper.GetStringMap("chains") {
+		sub := viper.Sub("chains." + name)
+		if sub == nil {
+			continue
+		}
+		chain := util.Chain{
+			RpcUrl:     sub.GetString("rpc_url"),
+			ChainID:    sub.GetUint64("chain_id"),
+			Currency:   sub.GetString("currency"),
+			BlockURL:   sub.GetString("block_url"),
+			TxURL:      sub.GetString("tx_url"),
+			AddressURL: sub.GetString("address_url"),
+			API: util.ContractAPI{
+				URL: sub.GetString("api_url"),
+				Key: sub.GetString("api_key"),
+			},
+			BeaconURL: sub.GetString("beacon_url"),
+			Labels:    sub.GetStringMapString("labels"),
+		}
+		if explorer := sub.GetString("explorer_url"); explorer != "" {
+			chain.SetExplorer(explorer)
+		}
+		if sub.IsSet("fee_market") {
+			chain.FeeMarket = &util.FeeMarket{
+				ElasticityMultiplier:     sub.GetUint64("fee_market.elasticity_multiplier"),
+				BaseFeeChangeDenominator: sub.GetUint64("fee_market.base_fee_change_denominator"),
+			}
+		}
+		conf.Chains[name] = chain
+	}
+	for name := range viper.GetStringMap("themes") {
+		conf.Themes[name] = viper.GetStringMapString("themes." + name)
 	}
 
 	config = conf
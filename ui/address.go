@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gdamore/tcell/v2"
+	"github.com/treethought/ethscan/util"
+)
+
+// AddressData shows the account state of an address.
+type AddressData struct {
+	*cview.TextView
+	app      *App
+	address  *common.Address
+	hasCode  bool
+	name     string
+	bindings *cbind.Configuration
+}
+
+func NewAddressData(app *App) *AddressData {
+	d := &AddressData{
+		TextView: cview.NewTextView(),
+		app:      app,
+	}
+	d.SetTitle(fmt.Sprintf("Address (hit `%s` to open as contract, `%s` for its ENS name)",
+		app.keys.Keys(ActionShowContract), app.keys.Keys(ActionShowENS)))
+	d.SetBorder(true)
+	d.SetDynamicColors(true)
+	d.initBindings()
+	return d
+}
+
+func (d *AddressData) initBindings() {
+	d.bindings = cbind.NewConfiguration()
+	d.app.keys.bind(d.bindings, ActionShowContract, d.handleContract)
+	d.app.keys.bind(d.bindings, ActionShowENS, d.handleENS)
+	d.SetInputCapture(d.bindings.Capture)
+}
+
+func (d *AddressData) handleContract(ev *tcell.EventKey) *tcell.EventKey {
+	if d.address == nil || !d.hasCode {
+		return nil
+	}
+	d.app.cmdBar.Run(fmt.Sprintf("contract %s", d.address.Hex()))
+	return nil
+}
+
+func (d *AddressData) handleENS(ev *tcell.EventKey) *tcell.EventKey {
+	if d.name == "" {
+		return nil
+	}
+	d.app.app.SetRoot(NewENSView(d.app, d.name), true)
+	return nil
+}
+
+func (d *AddressData) Position() (int, int) {
+	return d.GetScrollOffset()
+}
+
+func (d *AddressData) SetPosition(row, offset int) {
+	d.ScrollTo(row, offset)
+}
+
+func (d *AddressData) Update() {
+	d.address = d.app.State.address
+	d.hasCode = false
+	d.name = ""
+	if d.address == nil {
+		d.SetText("no address selected")
+		return
+	}
+	d.SetText(fmt.Sprintf("Loading %s...", d.address.Hex()))
+	go d.load(*d.address)
+}
+
+func (d *AddressData) load(addr common.Address) {
+	ctx := context.TODO()
+	var b strings.Builder
+	fmt.Fprintf(&b, "Address: %s\n", addr.Hex())
+
+	name, err := d.app.ens.Name(ctx, addr)
+	if err != nil && !errors.Is(err, util.ErrENSDisabled) && !errors.Is(err, util.ErrNoENS) {
+		d.app.log.Error("failed to get ens name: ", err)
+	}
+	if name != "" {
+		fmt.Fprintf(&b, "ENS: %s\n", d.app.theme.ENS.Render(cview.Escape(name)))
+	}
+
+	balance, err := d.app.client.BalanceAt(ctx, addr, nil)
+	if err != nil {
+		d.app.log.Error("failed to get balance: ", err)
+		fmt.Fprintf(&b, "Balance: %s\n", d.app.theme.Failure.Render(cview.Escape(err.Error())))
+	} else {
+		fmt.Fprintf(&b, "Balance: %s %s\n", util.WeiToEther(balance).String(), d.app.chain.Symbol())
+	}
+
+	nonce, err := d.app.client.NonceAt(ctx, addr, nil)
+	if err != nil {
+		d.app.log.Error("failed to get nonce: ", err)
+	} else {
+		fmt.Fprintf(&b, "Nonce: %d\n", nonce)
+	}
+
+	code, err := d.app.client.CodeAt(ctx, addr, nil)
+	if err != nil {
+		d.app.log.Error("failed to get code: ", err)
+	}
+	if len(code) > 0 {
+		fmt.Fprintf(&b, "Type: contract (%d bytes of code)\n", len(code))
+	} else {
+		b.WriteString("Type: externally owned account\n")
+	}
+
+	d.app.app.QueueUpdateDraw(func() {
+		if d.address == nil || *d.address != addr {
+			return
+		}
+		d.hasCode = len(code) > 0
+		d.name = name
+		d.SetText(b.String())
+	})
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/treethought/ethscan/util"
+)
+
+// blobPreview is the number of bytes of each blob shown
+const blobPreview = 256
+
+// BlobsView shows the blobs of a transaction, fetched from the beacon api.
+type BlobsView struct {
+	*cview.TextView
+	app      *App
+	block    *types.Block
+	txn      *types.Transaction
+	bindings *cbind.Configuration
+}
+
+func NewBlobsView(app *App, block *types.Block, txn *types.Transaction) *BlobsView {
+	v := &BlobsView{
+		TextView: cview.NewTextView(),
+		app:      app,
+		block:    block,
+		txn:      txn,
+	}
+	v.SetTitle(fmt.Sprintf("Blobs of %s", app.client.TxHash(txn).Hex()))
+	v.SetBorder(true)
+	v.SetDynamicColors(true)
+	v.SetScrollable(true)
+	v.SetText("Loading...")
+
+	v.bindings = cbind.NewConfiguration()
+	app.keys.bind(v.bindings, ActionBack, v.onDone)
+	app.keys.bind(v.bindings, ActionHelp, app.helpHandler("blobs", v))
+	v.SetInputCapture(v.bindings.Capture)
+
+	go v.load()
+	return v
+}
+
+func (v *BlobsView) onDone(ev *tcell.EventKey) *tcell.EventKey {
+	v.app.app.SetRoot(v.app.layout, true)
+	return nil
+}
+
+func (v *BlobsView) load() {
+	sidecars, err := v.fetch()
+	v.app.app.QueueUpdateDraw(func() {
+		if err != nil {
+			v.SetText(fmt.Sprintf("%s %s", v.app.theme.Failure.Render("failed to get blobs:"), cview.Escape(err.Error())))
+			return
+		}
+		v.SetText(v.render(sidecars))
+	})
+}
+
+func (v *BlobsView) fetch() (map[common.Hash]util.BlobSidecar, error) {
+	slot, err := v.app.beacon.Slot(v.block.Time())
+	if err != nil {
+		return nil, err
+	}
+	sidecars, err := v.app.beacon.BlobSidecars(slot)
+	if err != nil {
+		return nil, err
+	}
+	byHash := make(map[common.Hash]util.BlobSidecar, len(sidecars))
+	for _, s := range sidecars {
+		byHash[s.VersionedHash()] = s
+	}
+	return byHash, nil
+}
+
+func (v *BlobsView) render(sidecars map[common.Hash]util.BlobSidecar) string {
+	var b strings.Builder
+	for i, h := range v.txn.BlobHashes() {
+		fmt.Fprintf(&b, "%s %s\n", v.app.theme.Keyword.Render(fmt.Sprintf("Blob %d", i)), h.Hex())
+		s, ok := sidecars[h]
+		if !ok {
+			fmt.Fprintf(&b, "  %s\n\n", v.app.theme.Warning.Render("not served by the beacon node, it may have been pruned"))
+			continue
+		}
+		data := bytes.TrimRight(s.Blob, "\x00")
+		fmt.Fprintf(&b, "  Index: %d\n", s.Index)
+		fmt.Fprintf(&b, "  Commitment: %s\n", hexutil.Encode(s.Commitment))
+		fmt.Fprintf(&b, "  Proof: %s\n", hexutil.Encode(s.Proof))
+		fmt.Fprintf(&b, "  Size: %d bytes used of %d\n", len(data), len(s.Blob))
+		if len(data) > blobPreview {
+			data = data[:blobPreview]
+		}
+		fmt.Fprintf(&b, "  Data: %s\n\n", v.app.theme.Muted.Render(hexutil.Encode(data)))
+	}
+	return b.String()
+}
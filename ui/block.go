@@ -1,43 +1,36 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"math/big"
+	"sort"
+	"strings"
 
-	"code.rocketnine.space/tslocum/cbind"
 	"code.rocketnine.space/tslocum/cview"
 	"github.com/aquilax/truncate"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/gdamore/tcell/v2"
 	"github.com/treethought/ethscan/util"
 )
 
 type BlockData struct {
 	*cview.Grid
-	block    *types.Block
-	bindings *cbind.Configuration
-	app      *App
+	block *types.Block
+	txns  *TransactionTable
+	stats *cview.List
+	app   *App
+	// beacon chain info is filled in once fetched
+	consensus *cview.List
 }
 
 func NewBlockData(app *App, block *types.Block) *BlockData {
 	bd := &BlockData{app: app, block: block, Grid: cview.NewGrid()}
 
-	bd.setBindings()
 	bd.render()
 	return bd
 }
 
-func (d *BlockData) setBindings() {
-	bind := cbind.NewConfiguration()
-	bind.SetKey(tcell.ModNone, tcell.KeyEsc, d.focusBlocks)
-	d.bindings = bind
-	d.SetInputCapture(d.bindings.Capture)
-}
-
-func (d *BlockData) focusBlocks(_ev *tcell.EventKey) *tcell.EventKey {
-	d.app.ShowBlocks()
-	return _ev
-}
-
 func (d *BlockData) SetBlock(block *types.Block) {
 	d.app.app.QueueUpdateDraw(func() {
 		if d.block != nil && d.block.Hash() == block.Hash() {
@@ -47,6 +40,46 @@ func (d *BlockData) SetBlock(block *types.Block) {
 		d.render()
 	})
 }
+func (d *BlockData) Position() (int, int) {
+	if d.txns == nil {
+		return 0, 0
+	}
+	return d.txns.Position()
+}
+
+func (d *BlockData) SetPosition(row, offset int) {
+	if d.txns == nil {
+		return
+	}
+	d.txns.SetPosition(row, offset)
+}
+
+// Filter returns the filter of the transactions
+func (d *BlockData) Filter() string {
+	if d.txns == nil {
+		return ""
+	}
+	return d.txns.Filter()
+}
+
+func (d *BlockData) SetFilter(expr string) error {
+	if d.txns == nil {
+		return fmt.Errorf("no block selected")
+	}
+	return d.txns.SetFilter(expr)
+}
+
+func (d *BlockData) SortBy(column string, desc bool) error {
+	if d.txns == nil {
+		return fmt.Errorf("no block selected")
+	}
+	return d.txns.SortBy(column, desc)
+}
+
+func (d *BlockData) SortColumns() []string {
+	return fieldNames(txnFields)
+}
+
 func (d *BlockData) Update() {
 	curBlock := d.app.State.block
 	d.SetBlock(curBlock)
@@ -87,8 +120,18 @@ func (d *BlockData) blockHeaders() *cview.Flex {
 	details.AddItem(cview.NewListItem(fmt.Sprintf("Root: %s", root)))
 	details.AddItem(cview.NewListItem(fmt.Sprintf("ExtraData: %s", extraData)))
 
+	d.stats = cview.NewList()
+	d.stats.SetTitle("stats")
+	d.stats.SetBorder(true)
+
+	d.consensus = cview.NewList()
+	d.consensus.SetTitle("consensus")
+	d.consensus.SetBorder(true)
+
 	f.AddItem(basic, 0, 1, true)
 	f.AddItem(details, 0, 1, false)
+	f.AddItem(d.stats, 0, 1, false)
+	f.AddItem(d.consensus, 0, 1, false)
 	return f
 
 }
@@ -107,9 +150,173 @@ func (d *BlockData) render() {
 
 	d.AddItem(d.blockHeaders(), 0, 0, 1, 3, 0, 0, false)
 
-	txns := NewTransactionTable(d.app, d.block)
-	d.AddItem(txns, 1, 0, 2, 3, 0, 0, true)
+	prev := d.txns
+	d.txns = NewTransactionTable(d.app, d.block)
+	d.txns.changed = d.renderStats
+	if prev != nil {
+		// keep filtering and sorting the same way across blocks
+		d.txns.filterExpr, d.txns.filter = prev.filterExpr, prev.filter
+		d.txns.sortColumn, d.txns.sortDesc = prev.sortColumn, prev.sortDesc
+		d.txns.render()
+	}
+	// withdrawals are listed beside the transactions
+	if withdrawals := d.block.Withdrawals(); len(withdrawals) > 0 {
+		d.AddItem(d.txns, 1, 0, 2, 2, 0, 0, true)
+		d.AddItem(d.renderWithdrawals(withdrawals), 1, 2, 2, 1, 0, 0, false)
+	} else {
+		d.AddItem(d.txns, 1, 0, 2, 3, 0, 0, true)
+	}
+
+	d.app.app.SetFocus(d.txns)
+
+	d.renderConsensus(nil, nil)
+	go d.loadConsensus(d.app.ctx, d.block)
+}
+
+// loadConsensus fetches the slot of the block when a beacon api is
+// configured
+func (d *BlockData) loadConsensus(ctx context.Context, block *types.Block) {
+	var info *util.BeaconInfo
+	var err error
+	if d.app.beacon.URL != "" {
+		info, err = d.app.beacon.BlockInfo(block.Time())
+	}
+	d.app.app.QueueUpdateDraw(func() {
+		if d.block != block {
+			return
+		}
+		d.renderConsensus(info, err)
+	})
+}
+
+// renderConsensus shows the post merge fields of the block, which are nil
+// before the forks adding them
+func (d *BlockData) renderConsensus(info *util.BeaconInfo, beaconErr error) {
+	d.consensus.Clear()
+	add := func(format string, args ...interface{}) {
+		d.consensus.AddItem(cview.NewListItem(fmt.Sprintf(format, args...)))
+	}
+	short := func(h *common.Hash) string {
+		if h == nil {
+			return d.app.theme.Muted.Render("none")
+		}
+		return truncate.Truncate(h.Hex(), 20, "...", truncate.PositionMiddle)
+	}
+
+	header := d.block.Header()
+	add("Parent Beacon Root: %s", short(header.ParentBeaconRoot))
+	add("Withdrawals Root: %s", short(header.WithdrawalsHash))
+	if header.BlobGasUsed != nil && header.ExcessBlobGas != nil {
+		add("Blob Gas Used: %d | Excess: %d", *header.BlobGasUsed, *header.ExcessBlobGas)
+	}
+	if l1, ok := d.app.client.L1Origin(d.block); ok {
+		add("L1 Block: %d", l1.Number)
+		if l1.Hash != (common.Hash{}) {
+			add("L1 Hash: %s", short(&l1.Hash))
+			add("L1 Time: %s | Base Fee: %.2f Gwei", util.FormatUnixTime(l1.Time), util.WeiToGwei(l1.BaseFee))
+		}
+	}
+
+	switch {
+	case beaconErr != nil:
+		add("Beacon: %s", d.app.theme.Failure.Render(cview.Escape(beaconErr.Error())))
+	case info != nil:
+		finality := d.app.theme.Warning.Render(string(info.Finality))
+		if info.Finality == util.Finalized {
+			finality = d.app.theme.Success.Render(string(info.Finality))
+		}
+		add("Slot: %d | Epoch: %d", info.Slot, info.Epoch)
+		add("Proposer Index: %d", info.Proposer)
+		add("Finality: %s", finality)
+	}
+}
 
-	d.app.app.SetFocus(txns)
+func (d *BlockData) renderWithdrawals(withdrawals types.Withdrawals) *cview.Table {
+	t := cview.NewTable()
+	t.SetBorder(true)
+	t.SetFixed(1, 0)
+	t.SetSelectable(true, false)
+	t.SetSelectedStyle(d.app.theme.Selected.tcell())
 
+	total := new(big.Int)
+	for col, title := range []string{"Index", "Validator", "Address", fmt.Sprintf("Amount (%s)", d.app.chain.Symbol())} {
+		cell := cview.NewTableCell(title)
+		cell.SetSelectable(false)
+		t.SetCell(0, col, cell)
+	}
+	for i, w := range withdrawals {
+		addr := truncate.Truncate(w.Address.Hex(), truncSize, "...", truncate.PositionMiddle)
+		if label, ok := d.app.chain.Label(w.Address.Hex()); ok {
+			addr = d.app.theme.Label.Render(cview.Escape(label))
+		}
+		t.SetCell(i+1, 0, cview.NewTableCell(fmt.Sprint(w.Index)))
+		t.SetCell(i+1, 1, cview.NewTableCell(fmt.Sprint(w.Validator)))
+		t.SetCell(i+1, 2, cview.NewTableCell(addr))
+		t.SetCell(i+1, 3, cview.NewTableCell(util.WeiToEther(util.GweiToWei(w.Amount)).Text('f', 6)))
+		total.Add(total, util.GweiToWei(w.Amount))
+	}
+	t.SetTitle(fmt.Sprintf("Withdrawals (%d, %s %s)", len(withdrawals), util.WeiToEther(total).Text('f', 4), d.app.chain.Symbol()))
+
+	return t
+}
+
+// renderStats summarizes the transactions, the stats depending on
+// receipts fill in as they load
+func (d *BlockData) renderStats() {
+	stats := util.GetBlockStats(d.block, d.txns.receipts(), d.app.client.RollupTxs)
+	total := len(d.block.Transactions())
+	if stats.Receipts < total {
+		d.stats.SetTitle(fmt.Sprintf("stats (%d of %d receipts)", stats.Receipts, total))
+	} else {
+		d.stats.SetTitle("stats")
+	}
+
+	ether := func(wei *big.Int) string {
+		if wei == nil {
+			wei = new(big.Int)
+		}
+		return fmt.Sprintf("%s %s", util.WeiToEther(wei).Text('f', 6), d.app.chain.Symbol())
+	}
+	gwei := func(wei *big.Int) string {
+		if wei == nil {
+			return "-"
+		}
+		return util.WeiToGwei(wei).Text('f', 2)
+	}
+
+	var typs []int
+	for typ := range stats.Types {
+		typs = append(typs, int(typ))
+	}
+	sort.Ints(typs)
+	var counts []string
+	for _, typ := range typs {
+		counts = append(counts, fmt.Sprintf("%s %d", txnTypeName(uint8(typ)), stats.Types[uint8(typ)]))
+	}
+
+	pct := util.GasUsedPct(d.block.Header())
+
+	d.stats.Clear()
+	d.stats.AddItem(cview.NewListItem(fmt.Sprintf("Txns: %d (%s)", total, strings.Join(counts, ", "))))
+	d.stats.AddItem(cview.NewListItem(fmt.Sprintf("Created: %d | Failed: %d", stats.Creations, stats.Failed)))
+	d.stats.AddItem(cview.NewListItem(fmt.Sprintf("Gas Used: %s %.2f%%", gasBar(pct, 20), pct)))
+	d.stats.AddItem(cview.NewListItem(fmt.Sprintf("Gas Price (Gwei): min %s | median %s | max %s",
+		gwei(stats.MinGasPrice), gwei(stats.MedianGasPrice), gwei(stats.MaxGasPrice))))
+	d.stats.AddItem(cview.NewListItem(fmt.Sprintf("Fees: %s | Burnt: %s", ether(stats.Fees.Total), ether(stats.Fees.Burnt))))
+	d.stats.AddItem(cview.NewListItem(fmt.Sprintf("Priority Fees: %s", ether(stats.Fees.Tip))))
+	if stats.BlobGasUsed > 0 {
+		d.stats.AddItem(cview.NewListItem(fmt.Sprintf("Blob Gas: %d | Blob Fees: %s", stats.BlobGasUsed, ether(stats.Fees.Blob))))
+	}
+	if stats.Fees.L1 != nil && stats.Fees.L1.Sign() > 0 {
+		d.stats.AddItem(cview.NewListItem(fmt.Sprintf("L1 Fees: %s", ether(stats.Fees.L1))))
+	}
+}
+
+// gasBar draws a bar of the width filled to the percentage
+func gasBar(pct float64, width int) string {
+	filled := int(pct / 100 * float64(width))
+	if filled > width {
+		filled = width
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
 }
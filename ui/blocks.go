@@ -3,9 +3,11 @@ package ui
 import (
 	"context"
 	"fmt"
-	"log"
 	"math/big"
-	"time"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
 
 	"code.rocketnine.space/tslocum/cbind"
 	"code.rocketnine.space/tslocum/cview"
@@ -16,155 +18,331 @@ import (
 	"github.com/treethought/ethscan/util"
 )
 
-const truncSize = 12
+const (
+	truncSize = 12
+	// defaultBlockPage is the number of blocks loaded when paging back
+	// without a backfill configured
+	defaultBlockPage = 20
+	blockFetchers    = 4
+)
+
+var defaultBlockColumns = []string{"time", "number", "hash", "miner", "txs", "gasLimit", "gasUsed", "gasUsedPct", "baseFee", "burnt", "blobs", "extraData"}
 
 type blockRow struct {
 	header *types.Header
+	txs    int
+	// label of the fee recipient on the chain
+	label string
 }
 
-func newBlockRow(h *types.Header) blockRow {
-	return blockRow{header: h}
+func (r *blockRow) gasUsedPct() float64 {
+	return util.GasUsedPct(r.header)
 }
 
-func (r blockRow) Time() *cview.TableCell {
-	human := util.FormatUnixTime(r.header.Time)
-	return cview.NewTableCell(human)
+// burnt is the base fee burnt by the block's gas
+func (r *blockRow) burnt() *big.Int {
+	if r.header.BaseFee == nil {
+		return nil
+	}
+	return new(big.Int).Mul(r.header.BaseFee, new(big.Int).SetUint64(r.header.GasUsed))
 }
 
-func (r blockRow) Number() *cview.TableCell {
-	return cview.NewTableCell(r.header.Number.String())
+// builder names the block builder, from the fee recipient's label or the
+// extra data builders commonly sign blocks with
+func (r *blockRow) builder() string {
+	if r.label != "" {
+		return r.label
+	}
+	return printable(string(r.header.Extra))
 }
 
-func (r blockRow) Hash() *cview.TableCell {
-	hash := r.header.Hash().String()
-	tr := truncate.Truncate(hash, truncSize, "...", truncate.PositionMiddle)
-	cell := cview.NewTableCell(tr)
-	// set the row's reference
-	cell.SetReference(r.header.Number)
-	return cell
+func printable(s string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsPrint(r) {
+			return r
+		}
+		return -1
+	}, s))
 }
 
-func (r blockRow) Parent() *cview.TableCell {
-	hash := r.header.ParentHash.String()
-	tr := truncate.Truncate(hash, truncSize, "...", truncate.PositionMiddle)
-	return cview.NewTableCell(tr)
+func bigFloat(f *big.Float) float64 {
+	n, _ := f.Float64()
+	return n
 }
 
-func (r blockRow) Miner() *cview.TableCell {
-	addr := r.header.Coinbase.String()
-	tr := truncate.Truncate(addr, truncSize, "...", truncate.PositionMiddle)
-	return cview.NewTableCell(tr)
+// blockColumn is a column of the blocks table which can be shown, sorted
+// and filtered on
+type blockColumn struct {
+	name  string
+	title string
+	field filterField
+	text  func(t *BlockTable, r *blockRow) string
+	value func(r *blockRow) filterValue
 }
 
-func (r blockRow) GasLimit() *cview.TableCell {
-	val := fmt.Sprint(r.header.GasLimit)
-	tr := truncate.Truncate(val, truncSize, "...", truncate.PositionMiddle)
-	return cview.NewTableCell(tr)
-}
-func (r blockRow) GasUsed() *cview.TableCell {
-	val := fmt.Sprint(r.header.GasUsed)
-	tr := truncate.Truncate(val, truncSize, "...", truncate.PositionMiddle)
-	return cview.NewTableCell(tr)
+var blockColumns = []blockColumn{
+	{
+		name: "time", title: "Time", field: filterField{numeric: true},
+		text:  func(_ *BlockTable, r *blockRow) string { return util.FormatUnixTime(r.header.Time) },
+		value: func(r *blockRow) filterValue { return numValue(float64(r.header.Time)) },
+	},
+	{
+		name: "number", title: "Number", field: filterField{numeric: true},
+		text:  func(_ *BlockTable, r *blockRow) string { return r.header.Number.String() },
+		value: func(r *blockRow) filterValue { return numValue(float64(r.header.Number.Uint64())) },
+	},
+	{
+		name: "hash", title: "Hash",
+		text: func(_ *BlockTable, r *blockRow) string {
+			return truncate.Truncate(r.header.Hash().String(), truncSize, "...", truncate.PositionMiddle)
+		},
+		value: func(r *blockRow) filterValue { return textValue(r.header.Hash().String()) },
+	},
+	{
+		name: "parent", title: "Parent",
+		text: func(_ *BlockTable, r *blockRow) string {
+			return truncate.Truncate(r.header.ParentHash.String(), truncSize, "...", truncate.PositionMiddle)
+		},
+		value: func(r *blockRow) filterValue { return textValue(r.header.ParentHash.String()) },
+	},
+	{
+		name: "miner", title: "Miner",
+		text: func(t *BlockTable, r *blockRow) string {
+			if r.label != "" {
+				return t.app.theme.Label.Render(cview.Escape(r.label))
+			}
+			return truncate.Truncate(r.header.Coinbase.String(), truncSize, "...", truncate.PositionMiddle)
+		},
+		value: func(r *blockRow) filterValue { return textValue(r.label, r.header.Coinbase.String()) },
+	},
+	{
+		name: "builder", title: "Builder",
+		text:  func(_ *BlockTable, r *blockRow) string { return cview.Escape(r.builder()) },
+		value: func(r *blockRow) filterValue { return textValue(r.builder()) },
+	},
+	{
+		name: "txs", title: "Txns", field: filterField{numeric: true},
+		text:  func(_ *BlockTable, r *blockRow) string { return fmt.Sprint(r.txs) },
+		value: func(r *blockRow) filterValue { return numValue(float64(r.txs)) },
+	},
+	{
+		name: "blobs", title: "Blobs", field: filterField{numeric: true},
+		text: func(_ *BlockTable, r *blockRow) string {
+			if n := util.BlobCount(r.header); n >= 0 {
+				return fmt.Sprint(n)
+			}
+			return "-"
+		},
+		value: func(r *blockRow) filterValue {
+			if n := util.BlobCount(r.header); n >= 0 {
+				return numValue(float64(n))
+			}
+			return filterValue{}
+		},
+	},
+	{
+		name: "gasLimit", title: "GasLimit", field: filterField{numeric: true},
+		text:  func(_ *BlockTable, r *blockRow) string { return fmt.Sprint(r.header.GasLimit) },
+		value: func(r *blockRow) filterValue { return numValue(float64(r.header.GasLimit)) },
+	},
+	{
+		name: "gasUsed", title: "GasUsed", field: filterField{numeric: true, percent: true},
+		text: func(_ *BlockTable, r *blockRow) string { return fmt.Sprint(r.header.GasUsed) },
+		value: func(r *blockRow) filterValue {
+			v := numValue(float64(r.header.GasUsed))
+			v.pct = r.gasUsedPct()
+			return v
+		},
+	},
+	{
+		name: "gasUsedPct", title: "Gas %", field: filterField{numeric: true},
+		text:  func(_ *BlockTable, r *blockRow) string { return fmt.Sprintf("%.1f%%", r.gasUsedPct()) },
+		value: func(r *blockRow) filterValue { return numValue(r.gasUsedPct()) },
+	},
+	{
+		name: "baseFee", title: "BaseFee (Gwei)", field: filterField{numeric: true},
+		text: func(_ *BlockTable, r *blockRow) string {
+			if r.header.BaseFee == nil {
+				return "-"
+			}
+			return util.WeiToGwei(r.header.BaseFee).Text('f', 2)
+		},
+		value: func(r *blockRow) filterValue {
+			if r.header.BaseFee == nil {
+				return filterValue{}
+			}
+			return numValue(bigFloat(util.WeiToGwei(r.header.BaseFee)))
+		},
+	},
+	{
+		name: "burnt", title: "Burnt", field: filterField{numeric: true},
+		text: func(t *BlockTable, r *blockRow) string {
+			burnt := r.burnt()
+			if burnt == nil {
+				return "-"
+			}
+			return fmt.Sprintf("%s %s", util.WeiToEther(burnt).Text('f', 4), t.app.chain.Symbol())
+		},
+		value: func(r *blockRow) filterValue {
+			burnt := r.burnt()
+			if burnt == nil {
+				return filterValue{}
+			}
+			return numValue(bigFloat(util.WeiToEther(burnt)))
+		},
+	},
+	{
+		name: "stateRoot", title: "StateRoot",
+		text: func(_ *BlockTable, r *blockRow) string {
+			return truncate.Truncate(r.header.Root.String(), truncSize, "...", truncate.PositionMiddle)
+		},
+		value: func(r *blockRow) filterValue { return textValue(r.header.Root.String()) },
+	},
+	{
+		name: "extraData", title: "ExtraData",
+		text:  func(_ *BlockTable, r *blockRow) string { return cview.Escape(printable(string(r.header.Extra))) },
+		value: func(r *blockRow) filterValue { return textValue(printable(string(r.header.Extra))) },
+	},
 }
-func (r blockRow) BaseFee() *cview.TableCell {
-	val := r.header.BaseFee.String()
-	tr := truncate.Truncate(val, truncSize, "...", truncate.PositionMiddle)
-	return cview.NewTableCell(tr)
+
+// lookupBlockColumns returns the named columns
+func lookupBlockColumns(names []string) ([]blockColumn, error) {
+	var cols []blockColumn
+	for _, name := range names {
+		col, ok := findBlockColumn(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown block column %q, available: %s", name, strings.Join(blockColumnNames(), ", "))
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
 }
-func (r blockRow) StateRoot() *cview.TableCell {
-	val := r.header.Root.String()
-	tr := truncate.Truncate(val, truncSize, "...", truncate.PositionMiddle)
-	return cview.NewTableCell(tr)
+
+func findBlockColumn(name string) (blockColumn, bool) {
+	for _, col := range blockColumns {
+		if strings.EqualFold(col.name, name) {
+			return col, true
+		}
+	}
+	return blockColumn{}, false
 }
-func (r blockRow) TxnLength() *cview.TableCell {
-	val := r.header.Root.String()
-	tr := truncate.Truncate(val, truncSize, "...", truncate.PositionMiddle)
-	return cview.NewTableCell(tr)
+
+func blockColumnNames() []string {
+	names := make([]string, len(blockColumns))
+	for i, col := range blockColumns {
+		names[i] = col.name
+	}
+	return names
 }
-func (r blockRow) ExtraData() *cview.TableCell {
-	val := string(r.header.Extra)
-	// tr := truncate.Truncate(val, truncSize, "...", truncate.PositionEnd)
-	return cview.NewTableCell(val)
+
+func blockFilterFields() map[string]filterField {
+	fields := make(map[string]filterField, len(blockColumns))
+	for _, col := range blockColumns {
+		fields[col.name] = col.field
+	}
+	return fields
 }
 
 type BlockTable struct {
 	*cview.Table
 	app      *App
-	headers  []*types.Header
-	fields   []string
+	rows     []*blockRow
+	columns  []blockColumn
 	bindings *cbind.Configuration
 
-	ch chan *types.Header
+	filterExpr string
+	filter     filter
+	sortColumn string
+	sortDesc   bool
+	// shown is the number of rows matching the filter
+	shown int
+
+	// live blocks are held in pending while paused
+	paused  bool
+	pending []*blockRow
+	loading bool
+
+	ch chan *types.Block
 }
 
 func NewBlockTable(app *App) *BlockTable {
 	table := &BlockTable{
-		Table:   cview.NewTable(),
-		headers: nil,
-		ch:      make(chan *types.Header),
-		app:     app,
-	}
-	table.fields = []string{
-		"Time",
-		"Number",
-		"Hash",
-		"Miner",
-		"GasLimit",
-		"GasUsed",
-		"BaseFee",
-		"ExtraData",
+		Table:      cview.NewTable(),
+		app:        app,
+		sortColumn: "number",
+		sortDesc:   app.config.Blocks.NewestFirst,
 	}
+	names := app.config.Blocks.Columns
+	if len(names) == 0 {
+		names = defaultBlockColumns
+	}
+	// columns are validated on startup
+	table.columns, _ = lookupBlockColumns(names)
 
+	table.SetBorder(true)
 	table.SetBorders(true)
-	table.SetFixed(0, 0)
+	table.SetFixed(1, 0)
 	table.SetSelectable(true, false)
-	table.SetSelectedStyle(tcell.ColorBlueViolet, tcell.ColorDefault, 0)
+	table.SetSelectedStyle(app.theme.Selected.tcell())
 	table.SetSelectedFunc(table.handleSelect)
 
 	table.initBindings()
 
-	table.setTableHeader()
-	table.ch = app.broker.SubscribeHeaders()
+	table.render()
+	table.ch = app.broker.SubcribeBlocks()
 	return table
 
 }
 
 func (t *BlockTable) Update() {}
 
+func (t *BlockTable) Position() (int, int) {
+	row, _ := t.GetSelection()
+	offset, _ := t.GetOffset()
+	return row, offset
+}
+
+func (t *BlockTable) SetPosition(row, offset int) {
+	t.Select(row, 0)
+	t.SetOffset(offset, 0)
+}
+
 func (t *BlockTable) initBindings() {
 	t.bindings = cbind.NewConfiguration()
 	t.SetInputCapture(t.bindings.Capture)
-	t.bindings.SetRune(tcell.ModNone, 'o', t.handleOpen)
-	t.bindings.SetRune(tcell.ModNone, 'y', t.handleCopy)
+	t.app.keys.bind(t.bindings, ActionOpenBrowser, t.handleOpen)
+	t.app.keys.bind(t.bindings, ActionCopy, t.handleCopy)
+	t.app.keys.bind(t.bindings, ActionSort, t.handleSort)
+	t.app.keys.bind(t.bindings, ActionReverseSort, t.handleReverseSort)
+	t.app.keys.bind(t.bindings, ActionFilter, t.handleFilter)
+	t.app.keys.bind(t.bindings, ActionLoadOlder, t.handleLoadOlder)
+	t.app.keys.bind(t.bindings, ActionPause, t.handlePause)
 
 }
 
 func (t *BlockTable) getCurrentRef() *big.Int {
-
 	row, _ := t.GetSelection()
-	ref := t.GetCell(row, 2).GetReference()
-	num, ok := ref.(*big.Int)
+	r, ok := t.GetCell(row, 0).GetReference().(*blockRow)
 	if !ok {
 		t.app.log.Error("failed to get block ref")
 		return nil
 	}
-	return num
+	return r.header.Number
 }
 
 func (t *BlockTable) handleSelect(row, _c int) {
 	if row == 0 {
 		return
 	}
-	// referene is currently only set on hash cell = col 2
-	cell := t.GetCell(row, 2)
-	ref := cell.GetReference()
-	num, ok := ref.(*big.Int)
-	if !ok {
-		log.Fatal("reference was not a big.Int blockNumber")
+	num := t.getCurrentRef()
+	if num == nil {
+		t.app.notifyError("", fmt.Errorf("row %d has no block reference", row))
+		return
 	}
 	block, err := t.app.client.BlockByNumber(context.TODO(), num)
 	if err != nil {
-		t.app.log.Error("failed to get block by number", err)
+		t.app.notifyError("failed to get block by number", err)
+		return
 	}
 	t.app.State.SetBlock(block)
 	t.app.State.SetTxn(nil)
@@ -176,7 +354,11 @@ func (t *BlockTable) handleOpen(ev *tcell.EventKey) *tcell.EventKey {
 	if curNum == nil {
 		return nil
 	}
-	url := fmt.Sprintf("https://etherscan.io/block/%s", curNum.String())
+	url, err := t.app.chain.ExplorerURL(t.app.chain.BlockURL, curNum.String())
+	if err != nil {
+		t.app.notifyError("", err)
+		return nil
+	}
 	util.Openbrowser(url)
 	return nil
 
@@ -188,58 +370,356 @@ func (t *BlockTable) handleCopy(ev *tcell.EventKey) *tcell.EventKey {
 	}
 	err := clipboard.WriteAll(curNum.String())
 	if err != nil {
-		log.Fatal(err)
+		t.app.notifyError("failed to copy", err)
+		return nil
 	}
+	t.app.notify(fmt.Sprintf("copied block number %s", curNum))
+	return nil
+}
+
+// handleSort sorts by the next visible column
+func (t *BlockTable) handleSort(ev *tcell.EventKey) *tcell.EventKey {
+	next := 0
+	for i, col := range t.columns {
+		if col.name == t.sortColumn {
+			next = (i + 1) % len(t.columns)
+		}
+	}
+	t.sortColumn = t.columns[next].name
+	t.render()
+	return nil
+}
+
+func (t *BlockTable) handleReverseSort(ev *tcell.EventKey) *tcell.EventKey {
+	t.sortDesc = !t.sortDesc
+	t.render()
+	return nil
+}
+
+func (t *BlockTable) handleFilter(ev *tcell.EventKey) *tcell.EventKey {
+	t.app.cmdBar.Prompt(strings.TrimSpace("filter " + t.filterExpr))
+	return nil
+}
+
+// Filter returns the current filter expression
+func (t *BlockTable) Filter() string {
+	return t.filterExpr
+}
+
+// SetFilter shows only the blocks matching the expression, such as
+// `gasUsed > 90%, miner = flashbots`
+func (t *BlockTable) SetFilter(expr string) error {
+	f, err := parseFilter(expr, blockFilterFields())
+	if err != nil {
+		return err
+	}
+	t.filterExpr = expr
+	t.filter = f
+	t.render()
+	return nil
+}
+
+// SortBy sorts the rows by the named column
+func (t *BlockTable) SortBy(name string, desc bool) error {
+	col, ok := findBlockColumn(name)
+	if !ok {
+		return fmt.Errorf("unknown block column %q, available: %s", name, strings.Join(blockColumnNames(), ", "))
+	}
+	t.sortColumn = col.name
+	t.sortDesc = desc
+	t.render()
+	return nil
+}
+
+// SortColumns lists the columns which can be sorted by
+func (t *BlockTable) SortColumns() []string {
+	return blockColumnNames()
+}
 
+// SetColumns changes the visible columns
+func (t *BlockTable) SetColumns(names []string) error {
+	cols, err := lookupBlockColumns(names)
+	if err != nil {
+		return err
+	}
+	t.columns = cols
+	t.render()
 	return nil
 }
 
-func (t BlockTable) setTableHeader() {
-	for col, field := range t.fields {
-		cell := cview.NewTableCell(field)
+// Columns lists the visible columns
+func (t *BlockTable) Columns() []string {
+	names := make([]string, len(t.columns))
+	for i, col := range t.columns {
+		names[i] = col.name
+	}
+	return names
+}
+
+func (t *BlockTable) setTableHeader() {
+	for col, c := range t.columns {
+		title := c.title
+		if c.name == t.sortColumn {
+			if t.sortDesc {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		cell := cview.NewTableCell(title)
 		cell.SetSelectable(false)
 		t.SetCell(0, col, cell)
 	}
 }
 
-func (t BlockTable) addHeaderRow(r blockRow) {
-	row := t.GetRowCount()
-	t.SetCell(row, 0, r.Time())
-	t.SetCell(row, 1, r.Number())
-	t.SetCell(row, 2, r.Hash())
-	t.SetCell(row, 3, r.Miner())
-	t.SetCell(row, 4, r.GasLimit())
-	t.SetCell(row, 5, r.GasUsed())
-	t.SetCell(row, 6, r.BaseFee())
-	t.SetCell(row, 7, r.ExtraData())
+// visible returns the rows matching the filter in sort order
+func (t *BlockTable) visible() []*blockRow {
+	var rows []*blockRow
+	for _, r := range t.rows {
+		if t.filter.match(func(field string) filterValue {
+			col, _ := findBlockColumn(field)
+			return col.value(r)
+		}) {
+			rows = append(rows, r)
+		}
+	}
 
+	col, _ := findBlockColumn(t.sortColumn)
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := col.value(rows[i]), col.value(rows[j])
+		if t.sortDesc {
+			return b.less(a)
+		}
+		return a.less(b)
+	})
+	return rows
 }
 
-func (t BlockTable) addHeader(ctx context.Context, h *types.Header) {
-	row := t.GetRowCount()
-	t.SetCell(row, 0, cview.NewTableCell(time.Unix(int64(h.Time), 0).String()))
-	t.SetCell(row, 1, cview.NewTableCell(h.Number.String()))
-	t.SetCell(row, 2, cview.NewTableCell(h.Hash().String()))
-	t.SetCell(row, 3, cview.NewTableCell(h.ParentHash.String()))
-	t.SetCell(row, 4, cview.NewTableCell(h.Coinbase.String()))
-	t.SetCell(row, 5, cview.NewTableCell(fmt.Sprint(h.GasLimit)))
-	t.SetCell(row, 6, cview.NewTableCell(fmt.Sprint(h.GasUsed)))
-	t.SetCell(row, 7, cview.NewTableCell(h.BaseFee.String()))
-	t.SetCell(row, 8, cview.NewTableCell(h.Root.String()))
-	t.SetCell(row, 9, cview.NewTableCell(string(h.Extra)))
+// render redraws the rows, keeping the selected block selected
+func (t *BlockTable) render() {
+	selected := t.getSelectedRow()
+
+	t.Clear()
+	t.setTableHeader()
+	rows := t.visible()
+	for i, r := range rows {
+		for c, col := range t.columns {
+			cell := cview.NewTableCell(col.text(t, r))
+			if c == 0 {
+				// set the row's reference
+				cell.SetReference(r)
+			}
+			t.SetCell(i+1, c, cell)
+		}
+		if r == selected {
+			t.Select(i+1, 0)
+		}
+	}
+
+	t.shown = len(rows)
+	t.renderTitle()
+}
 
-	return
+func (t *BlockTable) renderTitle() {
+	title := fmt.Sprintf("Blocks (%d)", t.shown)
+	if t.filterExpr != "" {
+		title = fmt.Sprintf("Blocks (%d of %d, filter: %s)", t.shown, len(t.rows), t.filterExpr)
+	}
+	if t.loading {
+		title += " loading older..."
+	}
+	if t.paused {
+		title += fmt.Sprintf(" paused, %d new (hit `%s` to resume)", len(t.pending), t.app.keys.Keys(ActionPause))
+	}
+	t.SetTitle(cview.Escape(title))
+}
+
+func (t *BlockTable) getSelectedRow() *blockRow {
+	row, _ := t.GetSelection()
+	if row <= 0 || row >= t.GetRowCount() {
+		return nil
+	}
+	r, _ := t.GetCell(row, 0).GetReference().(*blockRow)
+	return r
+}
+
+func (t *BlockTable) newRow(block *types.Block) *blockRow {
+	r := &blockRow{header: block.Header(), txs: len(block.Transactions())}
+	r.label, _ = t.app.chain.Label(r.header.Coinbase.Hex())
+	return r
+}
+
+func (r *blockRow) number() uint64 {
+	return r.header.Number.Uint64()
+}
+
+// insert adds rows, replacing those of the same number, and trims the ring
+// to the configured size. Live blocks push out the oldest rows, paging back
+// pushes out the newest.
+func (t *BlockTable) insert(rows []*blockRow, keepOld bool) (evicted int) {
+	byNum := make(map[uint64]*blockRow, len(t.rows)+len(rows))
+	for _, r := range append(t.rows, rows...) {
+		byNum[r.number()] = r
+	}
+	t.rows = make([]*blockRow, 0, len(byNum))
+	for _, r := range byNum {
+		t.rows = append(t.rows, r)
+	}
+	sort.Slice(t.rows, func(i, j int) bool { return t.rows[i].number() < t.rows[j].number() })
+
+	if max := t.app.config.Blocks.MaxRows; max > 0 && len(t.rows) > max {
+		evicted = len(t.rows) - max
+		if keepOld {
+			t.rows = t.rows[:max]
+		} else {
+			t.rows = t.rows[evicted:]
+		}
+	}
+	t.render()
+	return evicted
+}
+
+func (t *BlockTable) addBlock(block *types.Block) {
+	if t.paused {
+		t.pending = append(t.pending, t.newRow(block))
+		if max := t.app.config.Blocks.MaxRows; max > 0 && len(t.pending) > max {
+			t.pending = t.pending[len(t.pending)-max:]
+		}
+		t.renderTitle()
+		return
+	}
+	t.insert([]*blockRow{t.newRow(block)}, false)
+}
 
+// fetchRange gets the blocks numbered from to to, skipping those which
+// fail
+func (t *BlockTable) fetchRange(ctx context.Context, from, to uint64) ([]*blockRow, error) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		rows    []*blockRow
+		lastErr error
+	)
+	sem := make(chan struct{}, blockFetchers)
+	for n := from; n <= to; n++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(n uint64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			block, err := t.app.client.BlockByNumber(ctx, new(big.Int).SetUint64(n))
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				lastErr = err
+				return
+			}
+			rows = append(rows, t.newRow(block))
+		}(n)
+	}
+	wg.Wait()
+	if len(rows) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return rows, nil
+}
+
+// backfill loads the blocks before the head so the table does not start
+// empty
+func (t *BlockTable) backfill(ctx context.Context) {
+	count := uint64(t.app.config.Blocks.Backfill)
+	if count == 0 {
+		return
+	}
+	head, err := t.app.client.BlockNumber(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			t.app.notifyError("failed to get the latest block", err)
+		}
+		return
+	}
+	from := uint64(0)
+	if head >= count {
+		from = head - count + 1
+	}
+	rows, err := t.fetchRange(ctx, from, head)
+	if err != nil {
+		if ctx.Err() == nil {
+			t.app.notifyError("failed to backfill blocks", err)
+		}
+		return
+	}
+	t.app.app.QueueUpdateDraw(func() {
+		t.insert(rows, false)
+	})
+}
+
+// handleLoadOlder pages back from the oldest block in the table
+func (t *BlockTable) handleLoadOlder(ev *tcell.EventKey) *tcell.EventKey {
+	if t.loading || len(t.rows) == 0 {
+		return nil
+	}
+	oldest := t.rows[0].number()
+	if oldest == 0 {
+		t.app.notify("reached the genesis block")
+		return nil
+	}
+	page := uint64(t.app.config.Blocks.Backfill)
+	if page == 0 {
+		page = defaultBlockPage
+	}
+	from := uint64(0)
+	if oldest > page {
+		from = oldest - page
+	}
+
+	t.loading = true
+	t.renderTitle()
+	go func() {
+		rows, err := t.fetchRange(t.app.ctx, from, oldest-1)
+		t.app.app.QueueUpdateDraw(func() {
+			t.loading = false
+			t.renderTitle()
+			if err != nil {
+				t.app.notifyError("failed to load older blocks", err)
+				return
+			}
+			if t.insert(rows, true) > 0 && !t.paused {
+				// the newest rows made room, so stop live blocks from
+				// pushing out the history
+				t.setPaused(true)
+				t.app.notify("paused live updates to keep older blocks")
+			}
+		})
+	}()
+	return nil
+}
+
+func (t *BlockTable) handlePause(ev *tcell.EventKey) *tcell.EventKey {
+	t.setPaused(!t.paused)
+	return nil
+}
+
+// setPaused stops or resumes adding live blocks, which are held until
+// resumed
+func (t *BlockTable) setPaused(paused bool) {
+	t.paused = paused
+	if !paused && len(t.pending) > 0 {
+		pending := t.pending
+		t.pending = nil
+		t.insert(pending, false)
+		return
+	}
+	t.renderTitle()
 }
 
-func (t BlockTable) watch(ctx context.Context) error {
+func (t *BlockTable) watch(ctx context.Context) error {
+	go t.backfill(ctx)
 	for {
 		select {
-		case header := <-t.ch:
+		case block := <-t.ch:
 			t.app.app.QueueUpdateDraw(func() {
-				row := newBlockRow(header)
-				t.addHeaderRow(row)
-				// t.Sort(1, true)
+				t.addBlock(block)
 			})
 		case <-ctx.Done():
 			return nil
@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gdamore/tcell/v2"
+	"github.com/treethought/ethscan/util"
+)
+
+// BytecodeView shows the disassembled runtime code of a contract, used
+// for contracts without verified source.
+type BytecodeView struct {
+	*cview.Flex
+	app       *App
+	address   common.Address
+	code      *cview.TextView
+	selectors *cview.TextView
+	metadata  *cview.TextView
+	bindings  *cbind.Configuration
+	db        *util.SignatureDB
+}
+
+func NewBytecodeView(app *App, address common.Address) *BytecodeView {
+	v := &BytecodeView{
+		Flex:      cview.NewFlex(),
+		app:       app,
+		address:   address,
+		code:      cview.NewTextView(),
+		selectors: cview.NewTextView(),
+		metadata:  cview.NewTextView(),
+		db:        util.NewSignatureDB(),
+	}
+	v.SetDirection(cview.FlexColumn)
+
+	v.code.SetTitle(fmt.Sprintf("Bytecode %s", address.Hex()))
+	v.selectors.SetTitle("Function Selectors")
+	v.metadata.SetTitle("Metadata")
+	for _, tv := range []*cview.TextView{v.code, v.selectors, v.metadata} {
+		tv.SetBorder(true)
+		tv.SetDynamicColors(true)
+		tv.SetScrollable(true)
+		tv.SetText("Loading...")
+	}
+
+	side := cview.NewFlex()
+	side.SetDirection(cview.FlexRow)
+	side.AddItem(v.selectors, 0, 2, false)
+	side.AddItem(v.metadata, 0, 1, false)
+
+	v.AddItem(v.code, 0, 2, true)
+	v.AddItem(side, 0, 1, false)
+
+	v.initBindings()
+	go v.load()
+	return v
+}
+
+func (v *BytecodeView) initBindings() {
+	v.bindings = cbind.NewConfiguration()
+	v.app.keys.bind(v.bindings, ActionBack, v.onDone)
+	v.app.keys.bind(v.bindings, ActionHelp, v.app.helpHandler("bytecode", v))
+	v.SetInputCapture(v.bindings.Capture)
+}
+
+func (v *BytecodeView) onDone(ev *tcell.EventKey) *tcell.EventKey {
+	v.app.app.SetRoot(v.app.layout, true)
+	return nil
+}
+
+func (v *BytecodeView) load() {
+	code, err := v.app.client.CodeAt(context.TODO(), v.address, nil)
+	if err != nil {
+		v.app.log.Error("failed to get code: ", err)
+		v.app.app.QueueUpdateDraw(func() {
+			v.code.SetText(fmt.Sprintf("%s %s", v.app.theme.Failure.Render("failed to get code:"), cview.Escape(err.Error())))
+		})
+		return
+	}
+	if len(code) == 0 {
+		v.app.app.QueueUpdateDraw(func() {
+			v.code.SetText("no code at address")
+			v.selectors.SetText("")
+			v.metadata.SetText("")
+		})
+		return
+	}
+
+	meta, err := util.ParseMetadata(code)
+	runtime := code
+	var metaText string
+	if err != nil {
+		metaText = fmt.Sprintf("no solidity metadata found (%s)", err)
+	} else {
+		runtime = code[:len(code)-meta.Length]
+		metaText = formatMetadata(meta)
+	}
+
+	instrs := util.Disassemble(runtime)
+	selectors := util.FunctionSelectors(instrs)
+
+	v.app.app.QueueUpdateDraw(func() {
+		v.code.SetText(formatInstructions(v.app.theme, instrs))
+		v.metadata.SetText(cview.Escape(metaText))
+		v.selectors.SetText(strings.Join(selectors, "\n"))
+	})
+
+	v.resolveSelectors(selectors)
+}
+
+// resolveSelectors looks up each selector in the signature db, updating
+// the list as results arrive
+func (v *BytecodeView) resolveSelectors(selectors []string) {
+	lines := make([]string, len(selectors))
+	copy(lines, selectors)
+	for i, sel := range selectors {
+		sig, err := v.db.GetSignature(sel)
+		if err != nil {
+			v.app.log.Debug("no signature for selector: ", sel)
+			continue
+		}
+		lines[i] = fmt.Sprintf("%s %s", sel, v.app.theme.Method.Render(cview.Escape(sig.TextSignature)))
+		text := strings.Join(lines, "\n")
+		v.app.app.QueueUpdateDraw(func() {
+			v.selectors.SetText(text)
+		})
+	}
+}
+
+func formatInstructions(theme *Theme, instrs []util.Instruction) string {
+	var b strings.Builder
+	for _, i := range instrs {
+		if i.Op == util.JUMPDEST {
+			fmt.Fprintf(&b, "%s\n", theme.Highlight.Render(fmt.Sprintf("0x%04x  %s", i.PC, i)))
+			continue
+		}
+		fmt.Fprintf(&b, "0x%04x  %s\n", i.PC, i)
+	}
+	return b.String()
+}
+
+func formatMetadata(meta *util.Metadata) string {
+	var b strings.Builder
+	if meta.Solc != "" {
+		fmt.Fprintf(&b, "Compiler: solc %s\n", meta.Solc)
+	}
+	if meta.IPFS != "" {
+		fmt.Fprintf(&b, "IPFS: %s\n", meta.IPFS)
+	}
+	if meta.Bzzr0 != "" {
+		fmt.Fprintf(&b, "Swarm (bzzr0): %s\n", meta.Bzzr0)
+	}
+	if meta.Bzzr1 != "" {
+		fmt.Fprintf(&b, "Swarm (bzzr1): %s\n", meta.Bzzr1)
+	}
+	if meta.Experimental {
+		b.WriteString("Experimental: true\n")
+	}
+	fmt.Fprintf(&b, "Trailer: %d bytes\n", meta.Length)
+	return b.String()
+}
@@ -0,0 +1,313 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gdamore/tcell/v2"
+	"github.com/treethought/ethscan/util"
+)
+
+// MethodCallView executes a contract method. View and pure methods are
+// run with eth_call, other methods are simulated without broadcasting.
+type MethodCallView struct {
+	*cview.Flex
+	app      *App
+	address  common.Address
+	abi      *abi.ABI
+	method   *abi.Method
+	form     *cview.Form
+	output   *cview.TextView
+	bindings *cbind.Configuration
+
+	args  []*cview.InputField
+	block *cview.InputField
+
+	// simulation inputs, only set for non-constant methods
+	from  *cview.InputField
+	value *cview.InputField
+	gas   *cview.InputField
+	fund  *cview.CheckBox
+}
+
+func NewMethodCallView(app *App, address common.Address, contractABI *abi.ABI, method *abi.Method) *MethodCallView {
+	v := &MethodCallView{
+		Flex:    cview.NewFlex(),
+		app:     app,
+		address: address,
+		abi:     contractABI,
+		method:  method,
+		form:    cview.NewForm(),
+		output:  cview.NewTextView(),
+	}
+
+	v.SetDirection(cview.FlexColumn)
+	v.SetTitle(fmt.Sprintf("%s @ %s", method.Sig, address.Hex()))
+	v.SetBorder(true)
+
+	v.form.SetTitle("Inputs")
+	v.form.SetBorder(true)
+	for _, in := range method.Inputs {
+		v.args = append(v.args, v.addInput(argLabel(in), ""))
+	}
+
+	if method.IsConstant() {
+		v.block = v.addInput("Block", "latest")
+		v.form.AddButton("Call", v.call)
+	} else {
+		v.from = v.addInput("From", "")
+		v.value = v.addInput("Value (wei)", "0")
+		v.gas = v.addInput("Gas", "")
+		v.block = v.addInput("Block", "latest")
+		v.fund = cview.NewCheckBox()
+		v.fund.SetLabel("Fund sender")
+		v.fund.SetChecked(true)
+		v.form.AddFormItem(v.fund)
+		v.form.AddButton("Simulate", v.simulate)
+	}
+	v.form.SetCancelFunc(v.close)
+
+	v.output.SetTitle("Outputs")
+	v.output.SetBorder(true)
+	v.output.SetDynamicColors(true)
+	v.output.SetScrollable(true)
+	v.output.SetWordWrap(true)
+
+	v.AddItem(v.form, 0, 1, true)
+	v.AddItem(v.output, 0, 1, false)
+
+	v.initBindings()
+	return v
+}
+
+func argLabel(arg abi.Argument) string {
+	if arg.Name == "" {
+		return arg.Type.String()
+	}
+	return fmt.Sprintf("%s (%s)", arg.Name, arg.Type)
+}
+
+func (v *MethodCallView) addInput(label, value string) *cview.InputField {
+	field := cview.NewInputField()
+	field.SetLabel(label)
+	field.SetText(value)
+	v.form.AddFormItem(field)
+	return field
+}
+
+func (v *MethodCallView) initBindings() {
+	v.bindings = cbind.NewConfiguration()
+	v.bindings.SetKey(tcell.ModNone, tcell.KeyEsc, v.onDone)
+	v.SetInputCapture(v.bindings.Capture)
+}
+
+func (v *MethodCallView) onDone(ev *tcell.EventKey) *tcell.EventKey {
+	v.close()
+	return nil
+}
+
+func (v *MethodCallView) close() {
+	v.app.app.SetRoot(v.app.layout, true)
+}
+
+func (v *MethodCallView) showError(prefix string, err error) {
+	v.output.SetText(fmt.Sprintf("%s %s", v.app.theme.Failure.Render(prefix+":"), cview.Escape(err.Error())))
+}
+
+func (v *MethodCallView) parseInputs() ([]interface{}, string, error) {
+	inputs := make([]string, len(v.args))
+	for i, field := range v.args {
+		inputs[i] = field.GetText()
+	}
+
+	args, err := util.ParseArgs(v.method.Inputs, inputs)
+	if err != nil {
+		return nil, "", err
+	}
+	block, err := util.ParseBlockTag(v.block.GetText())
+	if err != nil {
+		return nil, "", err
+	}
+	return args, block, nil
+}
+
+func (v *MethodCallView) call() {
+	args, block, err := v.parseInputs()
+	if err != nil {
+		v.showError("invalid input", err)
+		return
+	}
+
+	v.output.SetText("Calling...")
+	go func() {
+		results, err := util.CallMethod(context.TODO(), v.app.rpc, v.address, v.method, args, block)
+		v.app.app.QueueUpdateDraw(func() {
+			if err != nil {
+				v.app.log.Error("call failed: ", err)
+				v.showError("call failed", err)
+				return
+			}
+			v.output.SetText(formatOutputs(v.method.Outputs, results))
+		})
+	}()
+}
+
+func (v *MethodCallView) callMsg(args []interface{}) (util.CallMsg, error) {
+	msg := util.CallMsg{To: &v.address}
+
+	input, err := v.method.Inputs.Pack(args...)
+	if err != nil {
+		return msg, err
+	}
+	msg.Data = append(append([]byte{}, v.method.ID...), input...)
+
+	from := strings.TrimSpace(v.from.GetText())
+	if !common.IsHexAddress(from) {
+		return msg, fmt.Errorf("invalid from address: %q", from)
+	}
+	msg.From = common.HexToAddress(from)
+
+	value, err := util.ParseArg(abi.Type{T: abi.UintTy, Size: 256}, v.value.GetText())
+	if err != nil {
+		return msg, fmt.Errorf("invalid value: %w", err)
+	}
+	msg.Value = value.(*big.Int)
+
+	if gas := strings.TrimSpace(v.gas.GetText()); gas != "" {
+		msg.Gas, err = strconv.ParseUint(gas, 10, 64)
+		if err != nil {
+			return msg, fmt.Errorf("invalid gas: %s", gas)
+		}
+	}
+	return msg, nil
+}
+
+func (v *MethodCallView) simulate() {
+	args, block, err := v.parseInputs()
+	if err != nil {
+		v.showError("invalid input", err)
+		return
+	}
+	msg, err := v.callMsg(args)
+	if err != nil {
+		v.showError("invalid input", err)
+		return
+	}
+
+	fund := v.fund.IsChecked()
+	v.output.SetText("Simulating...")
+	go func() {
+		res, err := util.Simulate(context.TODO(), v.app.rpc, msg, block, fund)
+		v.app.app.QueueUpdateDraw(func() {
+			if err != nil {
+				v.app.log.Error("simulation failed: ", err)
+				v.showError("simulation failed", err)
+				return
+			}
+			if res.TraceErr != nil {
+				v.app.log.Error("debug_traceCall failed, fell back to eth_call: ", res.TraceErr)
+			}
+			v.output.SetText(formatSimulation(v.app.theme, v.abi, v.method, res))
+		})
+	}()
+}
+
+func formatOutputs(args abi.Arguments, results []interface{}) string {
+	var b strings.Builder
+	for i, r := range results {
+		label := fmt.Sprintf("[%d]", i)
+		if i < len(args) {
+			label = fmt.Sprintf("[%d] %s", i, argLabel(args[i]))
+		}
+		fmt.Fprintf(&b, "%s\n  %s\n", cview.Escape(label), cview.Escape(util.FormatValue(r)))
+	}
+	if len(results) == 0 {
+		b.WriteString("(no outputs)")
+	}
+	return b.String()
+}
+
+func formatSimulation(theme *Theme, contractABI *abi.ABI, method *abi.Method, res *util.SimulationResult) string {
+	var b strings.Builder
+
+	if res.Reverted {
+		fmt.Fprintf(&b, "Status: %s\n", theme.Failure.Render("Reverted"))
+		fmt.Fprintf(&b, "Error: %s\n", cview.Escape(res.Error))
+		if reason := util.DecodeRevert(contractABI, res.RevertData); reason != "" {
+			fmt.Fprintf(&b, "Revert Reason: %s\n", cview.Escape(reason))
+		}
+	} else {
+		fmt.Fprintf(&b, "Status: %s\n", theme.Success.Render("Success"))
+	}
+
+	if res.Traced {
+		fmt.Fprintf(&b, "Gas Used: %d\n", res.GasUsed)
+	}
+	if res.EstimateErr != nil {
+		fmt.Fprintf(&b, "Gas Estimate: %s\n", cview.Escape(res.EstimateErr.Error()))
+	} else {
+		fmt.Fprintf(&b, "Gas Estimate: %d\n", res.GasEstimate)
+	}
+
+	if !res.Reverted && method != nil {
+		b.WriteString("\nReturn Data:\n")
+		results, err := method.Outputs.Unpack(res.ReturnData)
+		if err != nil {
+			fmt.Fprintf(&b, "  %s\n", hexutil.Encode(res.ReturnData))
+		} else {
+			b.WriteString(formatOutputs(method.Outputs, results))
+			b.WriteString("\n")
+		}
+	}
+
+	if !res.Traced {
+		b.WriteString("\nLogs: unavailable (node does not support debug_traceCall)\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\nLogs (%d):\n", len(res.Logs))
+	for i, l := range res.Logs {
+		fmt.Fprintf(&b, "%s %s\n", cview.Escape(fmt.Sprintf("[%d]", i)), cview.Escape(formatLog(contractABI, l)))
+	}
+	return b.String()
+}
+
+// formatLog decodes a simulated log with the contract abi when the event
+// is known, falling back to the raw topics and data.
+func formatLog(contractABI *abi.ABI, l util.SimulatedLog) string {
+	raw := fmt.Sprintf("%s topics=%v data=%s", l.Address.Hex(), l.Topics, hexutil.Encode(l.Data))
+	if contractABI == nil || len(l.Topics) == 0 {
+		return raw
+	}
+	event, err := contractABI.EventByID(l.Topics[0])
+	if err != nil {
+		return raw
+	}
+
+	values := make(map[string]interface{})
+	if err := event.Inputs.UnpackIntoMap(values, l.Data); err != nil {
+		return raw
+	}
+	var indexed abi.Arguments
+	for _, in := range event.Inputs {
+		if in.Indexed {
+			indexed = append(indexed, in)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, l.Topics[1:]); err != nil {
+		return raw
+	}
+
+	fields := make([]string, 0, len(event.Inputs))
+	for _, in := range event.Inputs {
+		fields = append(fields, fmt.Sprintf("%s: %s", in.Name, util.FormatValue(values[in.Name])))
+	}
+	return fmt.Sprintf("%s %s(%s)", l.Address.Hex(), event.Name, strings.Join(fields, ", "))
+}
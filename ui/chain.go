@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/treethought/ethscan/util"
+)
+
+const connectTimeout = 15 * time.Second
+
+// connection holds the clients of a chain
+type connection struct {
+	chain  util.Chain
+	rpc    *rpc.Client
+	client *util.Client
+}
+
+// connect dials the rpc of the chain and checks its chain id. Unnamed
+// profiles are detected from the chain id.
+func connect(ctx context.Context, config *Config, chain util.Chain) (*connection, error) {
+	rpcClient, err := rpc.DialContext(ctx, chain.RpcUrl)
+	if err != nil {
+		return nil, err
+	}
+	client := util.NewClient(rpcClient)
+
+	id, err := client.ChainID(ctx)
+	if err != nil {
+		rpcClient.Close()
+		return nil, fmt.Errorf("failed to get chain id of %s: %w", redactEndpoint(chain.RpcUrl), err)
+	}
+
+	switch {
+	case chain.Name == "":
+		detected := config.profileByID(id.Uint64())
+		detected.RpcUrl = chain.RpcUrl
+		chain = detected
+	case chain.ChainID == 0:
+		chain.ChainID = id.Uint64()
+	case chain.ChainID != id.Uint64():
+		rpcClient.Close()
+		return nil, fmt.Errorf("%s is chain %s, but profile %s expects chain %d",
+			redactEndpoint(chain.RpcUrl), id, chain.Name, chain.ChainID)
+	}
+	return &connection{chain: chain, rpc: rpcClient, client: client}, nil
+}
+
+// use replaces the clients, and everything bound to them, with those of
+// the connection
+func (a *App) use(conn *connection) {
+	a.chain = &conn.chain
+	a.rpc = conn.rpc
+	a.client = conn.client
+	a.broker = util.NewBroker(conn.client)
+	a.ens = util.NewENS(conn.client, a.config.DisableENS)
+	a.resolver = util.NewResolver(conn.client, conn.rpc, a.ens)
+	a.beacon = util.NewBeaconAPI(conn.chain.BeaconURL)
+	a.senders = util.NewSenders(conn.rpc, new(big.Int).SetUint64(conn.chain.ChainID), conn.client.RollupTxs)
+	a.ctx, a.cancel = context.WithCancel(context.Background())
+}
+
+// listen starts following the chain until the connection is replaced
+func (a *App) listen() {
+	ctx, broker := a.ctx, a.broker
+	go func() {
+		onErr := func(err error) {
+			if ctx.Err() == nil {
+				a.notifyError("", err)
+			}
+		}
+		if err := broker.ListenForBlocks(ctx, onErr); err != nil && ctx.Err() == nil {
+			a.notifyError("failed to subscribe to new blocks", err)
+		}
+	}()
+	go a.status.watch(ctx)
+}
+
+// switchChain connects to the named chain profile and resets the views,
+// it is called outside of the ui goroutine
+func (a *App) switchChain(ctx context.Context, name string) (string, error) {
+	chain, err := a.config.profile(name)
+	if err != nil {
+		return "", err
+	}
+	if chain.RpcUrl == "" {
+		return "", fmt.Errorf("chain profile %q has no rpc_url", name)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+	conn, err := connect(ctx, a.config, chain)
+	if err != nil {
+		return "", err
+	}
+
+	a.app.QueueUpdateDraw(func() {
+		old := a.rpc
+		a.cancel()
+		a.use(conn)
+		a.State = &State{}
+		a.views = make(map[string]View)
+		a.initViews()
+		a.app.SetRoot(a.layout, true)
+		a.listen()
+		old.Close()
+	})
+	return fmt.Sprintf("switched to %s", conn.chain.String()), nil
+}
@@ -0,0 +1,538 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/treethought/ethscan/util"
+)
+
+const maxCommandHistory = 50
+
+type command struct {
+	name  string
+	usage string
+	// complete returns suggestions for the argument of the command
+	complete func(arg string) []string
+	// run executes the command outside of the ui goroutine, returning a
+	// status message to show in the bar
+	run func(ctx context.Context, arg string) (string, error)
+}
+
+// CommandBar is a k9s style `:` prompt used to navigate between views.
+type CommandBar struct {
+	*cview.InputField
+	app      *App
+	commands map[string]*command
+	// mode is `:` for commands, `/` for search or `find` to find in a view
+	mode    string
+	hint    string
+	history map[string][]string
+	// finding is the view searched as the find query is typed
+	finding Findable
+}
+
+// Findable views search their rows incrementally
+type Findable interface {
+	// Find selects the first match of the query, an empty query clears
+	// the search
+	Find(query string)
+}
+
+func NewCommandBar(app *App) *CommandBar {
+	c := &CommandBar{
+		InputField: cview.NewInputField(),
+		app:        app,
+		commands:   make(map[string]*command),
+		history:    make(map[string][]string),
+	}
+	c.SetFieldBackgroundColor(tcell.ColorDefault)
+	c.hint = fmt.Sprintf("hit `%s` for commands, `%s` to search, `%s` for help",
+		app.keys.Keys(ActionCommand), app.keys.Keys(ActionSearch), app.keys.Keys(ActionHelp))
+	c.SetPlaceholder(c.hint)
+	c.SetDoneFunc(c.onDone)
+	c.SetAutocompleteFunc(c.autocomplete)
+	c.SetChangedFunc(c.onChanged)
+	c.initCommands()
+	return c
+}
+
+func (c *CommandBar) initCommands() {
+	tags := []string{"latest", "pending", "safe", "finalized", "earliest"}
+	c.register(&command{
+		name:     "block",
+		usage:    "block <number|hash|tag>",
+		complete: func(arg string) []string { return tags },
+		run:      c.runKind(util.RefBlock),
+	})
+	c.register(&command{
+		name:  "tx",
+		usage: "tx <hash>",
+		run:   c.runKind(util.RefTxn),
+	})
+	c.register(&command{
+		name:  "addr",
+		usage: "addr <address|ens name>",
+		complete: func(arg string) []string {
+			return c.knownAddresses()
+		},
+		run: c.runKind(util.RefAddress),
+	})
+	c.register(&command{
+		name:  "contract",
+		usage: "contract <address|ens name>",
+		complete: func(arg string) []string {
+			return c.knownAddresses()
+		},
+		run: c.runContract,
+	})
+	c.register(&command{
+		name:  "ens",
+		usage: "ens <name>",
+		run:   c.runENS,
+	})
+	c.register(&command{
+		name:  "mempool",
+		usage: "mempool",
+		run:   c.runMempool,
+	})
+	c.register(&command{
+		name:  "gas",
+		usage: "gas",
+		run:   c.runGas,
+	})
+	c.register(&command{
+		name:  "chain",
+		usage: "chain [profile]",
+		complete: func(arg string) []string {
+			return c.app.config.profileNames()
+		},
+		run: c.runChain,
+	})
+	c.register(&command{
+		name:  "log",
+		usage: "log",
+		run: func(ctx context.Context, arg string) (string, error) {
+			c.app.app.QueueUpdateDraw(func() {
+				c.app.app.SetRoot(NewLogView(c.app), true)
+			})
+			return "", nil
+		},
+	})
+	c.register(&command{
+		name:  "filter",
+		usage: "filter [expression]",
+		run:   c.runFilter,
+	})
+	c.register(&command{
+		name:  "sort",
+		usage: "sort <column> [asc|desc]",
+		complete: func(arg string) []string {
+			if v, ok := c.app.currentView().(Filterable); ok {
+				return v.SortColumns()
+			}
+			return nil
+		},
+		run: c.runSort,
+	})
+	c.register(&command{
+		name:  "columns",
+		usage: "columns <column,...>",
+		complete: func(arg string) []string {
+			if v, ok := c.app.currentView().(Columnar); ok {
+				return []string{strings.Join(v.Columns(), ",")}
+			}
+			return nil
+		},
+		run: c.runColumns,
+	})
+}
+
+func (c *CommandBar) register(cmd *command) {
+	c.commands[cmd.name] = cmd
+}
+
+// Activate focuses the prompt, suggesting recent commands
+func (c *CommandBar) Activate() {
+	c.activate(":")
+}
+
+// ActivateSearch focuses the prompt for a search of any identifier
+func (c *CommandBar) ActivateSearch() {
+	c.activate("/")
+}
+
+// ActivateFind focuses the prompt to search the view as the query is typed
+func (c *CommandBar) ActivateFind(v Findable) {
+	c.activate("find")
+	c.SetLabel("/")
+	c.finding = v
+}
+
+// Prompt opens the command prompt with the input filled in
+func (c *CommandBar) Prompt(input string) {
+	c.activate(":")
+	c.SetText(input + " ")
+}
+
+func (c *CommandBar) activate(mode string) {
+	c.mode = mode
+	c.SetLabel(mode)
+	c.SetPlaceholder("")
+	c.SetText("")
+	c.app.app.SetFocus(c)
+	c.Autocomplete()
+}
+
+func (c *CommandBar) onChanged(text string) {
+	if c.finding != nil {
+		c.finding.Find(text)
+	}
+}
+
+func (c *CommandBar) deactivate() {
+	c.SetLabel("")
+	c.SetText("")
+	c.SetPlaceholder(c.hint)
+	c.app.app.SetFocus(c.app.root)
+}
+
+func (c *CommandBar) onDone(key tcell.Key) {
+	switch key {
+	case tcell.KeyEnter:
+		input := strings.TrimSpace(c.GetText())
+		// keep the found matches to step through
+		c.finding = nil
+		c.deactivate()
+		if input == "" || c.mode == "find" {
+			return
+		}
+		if c.mode == "/" {
+			c.Search(input)
+			return
+		}
+		c.Run(input)
+	case tcell.KeyEsc:
+		c.deactivate()
+		c.finding = nil
+	}
+}
+
+// Run parses and executes a command line such as `block 1234`
+func (c *CommandBar) Run(input string) {
+	name, arg := splitCommand(input)
+	cmd, ok := c.lookup(name)
+	if !ok {
+		c.app.notifyError("", fmt.Errorf("unknown command: %s", name))
+		return
+	}
+	c.addHistory(":", input)
+	c.SetPlaceholder(fmt.Sprintf("%s...", input))
+	go c.finish(cmd.run(context.TODO(), arg))
+}
+
+// Search resolves any identifier, opening it directly when there is a
+// single match or listing the matches otherwise
+func (c *CommandBar) Search(input string) {
+	c.addHistory("/", input)
+	c.SetPlaceholder(fmt.Sprintf("searching %s...", input))
+	go func() {
+		ctx := context.TODO()
+		refs, err := c.app.resolver.Resolve(ctx, input)
+		if err != nil {
+			c.finish("", err)
+			return
+		}
+		if len(refs) == 1 {
+			c.finish(c.open(ctx, refs[0]))
+			return
+		}
+		c.app.app.QueueUpdateDraw(func() {
+			c.SetPlaceholder(c.hint)
+			c.app.app.SetRoot(NewSearchResults(c.app, input, refs), true)
+		})
+	}()
+}
+
+// finish shows the outcome of a command, it is called outside of the ui
+// goroutine
+func (c *CommandBar) finish(status string, err error) {
+	c.app.app.QueueUpdateDraw(func() {
+		c.SetPlaceholder(c.hint)
+	})
+	if err != nil {
+		c.app.notifyError("command failed", err)
+		return
+	}
+	if status != "" {
+		c.app.notify(status)
+	}
+}
+
+// lookup finds a command by name or by a unique prefix of its name
+func (c *CommandBar) lookup(name string) (*command, bool) {
+	if cmd, ok := c.commands[name]; ok {
+		return cmd, true
+	}
+	var match *command
+	for n, cmd := range c.commands {
+		if strings.HasPrefix(n, name) {
+			if match != nil {
+				return nil, false
+			}
+			match = cmd
+		}
+	}
+	return match, match != nil
+}
+
+func (c *CommandBar) addHistory(mode, input string) {
+	history := c.history[mode]
+	for i, h := range history {
+		if h == input {
+			history = append(history[:i], history[i+1:]...)
+			break
+		}
+	}
+	history = append([]string{input}, history...)
+	if len(history) > maxCommandHistory {
+		history = history[:maxCommandHistory]
+	}
+	c.history[mode] = history
+}
+
+func (c *CommandBar) autocomplete(text string) []*cview.ListItem {
+	if c.mode == "find" {
+		return nil
+	}
+	var suggestions []string
+	for _, h := range c.history[c.mode] {
+		if strings.HasPrefix(h, text) && h != text {
+			suggestions = append(suggestions, h)
+		}
+	}
+
+	if text != "" && c.mode == ":" {
+		name, arg := splitCommand(text)
+		if !strings.Contains(text, " ") {
+			var names []string
+			for n := range c.commands {
+				if strings.HasPrefix(n, name) && n != name {
+					names = append(names, n)
+				}
+			}
+			sort.Strings(names)
+			suggestions = append(suggestions, names...)
+		} else if cmd, ok := c.commands[name]; ok && cmd.complete != nil {
+			for _, s := range cmd.complete(arg) {
+				if strings.HasPrefix(s, arg) && s != arg {
+					suggestions = append(suggestions, fmt.Sprintf("%s %s", name, s))
+				}
+			}
+		}
+	}
+
+	items := make([]*cview.ListItem, 0, len(suggestions))
+	for _, s := range suggestions {
+		items = append(items, cview.NewListItem(s))
+	}
+	return items
+}
+
+// knownAddresses suggests addresses from the current selection
+func (c *CommandBar) knownAddresses() []string {
+	var addrs []string
+	if c.app.State.contractAddress != nil {
+		addrs = append(addrs, c.app.State.contractAddress.Hex())
+	}
+	if c.app.State.address != nil {
+		addrs = append(addrs, c.app.State.address.Hex())
+	}
+	if txn := c.app.State.txn; txn != nil && txn.To() != nil {
+		addrs = append(addrs, txn.To().Hex())
+	}
+	return addrs
+}
+
+func splitCommand(input string) (string, string) {
+	input = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(input), ":"))
+	parts := strings.SplitN(input, " ", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], strings.TrimSpace(parts[1])
+}
+
+// open navigates to the view for a resolved reference, it is called
+// outside of the ui goroutine
+func (c *CommandBar) open(ctx context.Context, ref util.Ref) (string, error) {
+	switch ref.Kind {
+	case util.RefBlock:
+		block, err := c.app.client.BlockByHash(ctx, ref.Hash)
+		if err != nil {
+			return "", err
+		}
+		c.app.app.QueueUpdateDraw(func() {
+			c.app.State.SetBlock(block)
+			c.app.State.SetTxn(nil)
+			c.app.ShowBlockData(block)
+		})
+
+	case util.RefTxn:
+		txn, pending, err := c.app.client.TransactionByHash(ctx, ref.Hash)
+		if err != nil {
+			return "", err
+		}
+		if pending {
+			return "", fmt.Errorf("transaction %s is still pending", ref.Hash.Hex())
+		}
+		rec, err := c.app.client.TransactionReceipt(ctx, ref.Hash)
+		if err != nil {
+			return "", err
+		}
+		block, err := c.app.client.BlockByHash(ctx, rec.BlockHash)
+		if err != nil {
+			return "", err
+		}
+		c.app.app.QueueUpdateDraw(func() {
+			c.app.State.SetBlock(block)
+			c.app.State.SetTxn(txn)
+			c.app.ShowTransactonData(txn)
+		})
+
+	case util.RefAddress:
+		addr := ref.Address
+		c.app.app.QueueUpdateDraw(func() {
+			c.app.State.SetAddress(&addr)
+			c.app.ShowAddressData(addr)
+		})
+
+	case util.RefSelector, util.RefTopic:
+		// there is no view for signatures, so show them in the bar
+		return ref.String(), nil
+	}
+	return "", nil
+}
+
+func (c *CommandBar) runKind(kind util.RefKind) func(ctx context.Context, arg string) (string, error) {
+	return func(ctx context.Context, arg string) (string, error) {
+		ref, err := c.app.resolver.ResolveKind(ctx, kind, arg)
+		if err != nil {
+			return "", err
+		}
+		return c.open(ctx, *ref)
+	}
+}
+
+func (c *CommandBar) runContract(ctx context.Context, arg string) (string, error) {
+	ref, err := c.app.resolver.ResolveKind(ctx, util.RefAddress, arg)
+	if err != nil {
+		return "", err
+	}
+	c.app.State.SetContract(&ref.Address)
+	// the contract view fetches from etherscan, so update it before
+	// switching to it
+	c.app.views["contract"].Update()
+	c.app.app.QueueUpdateDraw(func() {
+		c.app.ShowView("contract")
+	})
+	return "", nil
+}
+
+func (c *CommandBar) runENS(ctx context.Context, arg string) (string, error) {
+	if arg == "" {
+		return "", fmt.Errorf("usage: ens <name>")
+	}
+	if !c.app.ens.Enabled() {
+		return "", util.ErrENSDisabled
+	}
+	c.app.app.QueueUpdateDraw(func() {
+		c.app.app.SetRoot(NewENSView(c.app, arg), true)
+	})
+	return "", nil
+}
+
+func (c *CommandBar) runMempool(ctx context.Context, arg string) (string, error) {
+	c.app.app.QueueUpdateDraw(func() {
+		c.app.views["mempool"].Update()
+		c.app.ShowView("mempool")
+	})
+	return "", nil
+}
+
+func (c *CommandBar) runGas(ctx context.Context, arg string) (string, error) {
+	c.app.app.QueueUpdateDraw(func() {
+		c.app.views["gas"].Update()
+		c.app.ShowView("gas")
+	})
+	return "", nil
+}
+
+func (c *CommandBar) runChain(ctx context.Context, arg string) (string, error) {
+	if arg == "" {
+		return fmt.Sprintf("connected to %s", c.app.chain.String()), nil
+	}
+	return c.app.switchChain(ctx, arg)
+}
+
+// inView runs f with the current view in the ui goroutine, returning its
+// error
+func (c *CommandBar) inView(f func(v View) error) error {
+	errc := make(chan error, 1)
+	c.app.app.QueueUpdateDraw(func() {
+		errc <- f(c.app.currentView())
+	})
+	return <-errc
+}
+
+func (c *CommandBar) runFilter(ctx context.Context, arg string) (string, error) {
+	return "", c.inView(func(v View) error {
+		f, ok := v.(Filterable)
+		if !ok {
+			return fmt.Errorf("the %s view can not be filtered", c.app.State.current.Label())
+		}
+		return f.SetFilter(arg)
+	})
+}
+
+func (c *CommandBar) runSort(ctx context.Context, arg string) (string, error) {
+	fields := strings.Fields(arg)
+	if len(fields) == 0 || len(fields) > 2 {
+		return "", fmt.Errorf("usage: sort <column> [asc|desc]")
+	}
+	desc := false
+	if len(fields) == 2 {
+		switch fields[1] {
+		case "asc":
+		case "desc":
+			desc = true
+		default:
+			return "", fmt.Errorf("sort order must be asc or desc, got %q", fields[1])
+		}
+	}
+	return "", c.inView(func(v View) error {
+		f, ok := v.(Filterable)
+		if !ok {
+			return fmt.Errorf("the %s view can not be sorted", c.app.State.current.Label())
+		}
+		return f.SortBy(fields[0], desc)
+	})
+}
+
+func (c *CommandBar) runColumns(ctx context.Context, arg string) (string, error) {
+	names := strings.FieldsFunc(arg, func(r rune) bool { return r == ',' || r == ' ' })
+	if len(names) == 0 {
+		return "", fmt.Errorf("usage: columns <column,...>")
+	}
+	return "", c.inView(func(v View) error {
+		cols, ok := v.(Columnar)
+		if !ok {
+			return fmt.Errorf("the %s view has no configurable columns", c.app.State.current.Label())
+		}
+		return cols.SetColumns(names)
+	})
+}
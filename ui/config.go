@@ -1,7 +1,136 @@
 package ui
 
+import (
+	"fmt"
+	"sort"
+
+	"github.com/treethought/ethscan/util"
+)
+
 type Config struct {
 	RpcUrl       string `yaml:"rpc_url,omitempty"`
 	EtherscanKey string `yaml:"etherscan_key,omitempty"`
 	DisableENS   bool   `yaml:"disable_ens,omitempty"`
+	// Keymap overrides the keys bound to actions
+	Keymap map[string][]string `yaml:"keymap,omitempty"`
+	// Theme names a built in theme or one of Themes
+	Theme string `yaml:"theme,omitempty"`
+	// Themes defines custom themes, mapping styles to cview tags
+	Themes map[string]map[string]string `yaml:"themes,omitempty"`
+	// Chain names the profile to start with, when empty the chain of
+	// rpc_url is detected from its chain id
+	Chain string `yaml:"chain,omitempty"`
+	// Chains defines chain profiles, extending the built in profiles of
+	// the same name
+	Chains map[string]util.Chain `yaml:"chains,omitempty"`
+	Blocks BlocksConfig          `yaml:"blocks,omitempty"`
+}
+
+// BlocksConfig configures the blocks table
+type BlocksConfig struct {
+	// Columns lists the visible columns, see blockColumns
+	Columns     []string `yaml:"columns,omitempty"`
+	NewestFirst bool     `yaml:"newest_first"`
+	// Backfill is the number of blocks loaded on startup, and when paging
+	// back
+	Backfill int `yaml:"backfill"`
+	// MaxRows caps the number of blocks kept
+	MaxRows int `yaml:"max_rows"`
+}
+
+// Validate checks the config for errors which should prevent startup
+func (c *Config) Validate() error {
+	if _, err := NewKeymap(c.Keymap); err != nil {
+		return err
+	}
+	for name := range c.Themes {
+		if _, err := NewTheme(name, c.Themes); err != nil {
+			return err
+		}
+	}
+	if _, err := NewTheme(c.Theme, c.Themes); err != nil {
+		return err
+	}
+	if _, err := lookupBlockColumns(c.Blocks.Columns); err != nil {
+		return err
+	}
+	if c.Blocks.Backfill < 0 || c.Blocks.MaxRows < 1 {
+		return fmt.Errorf("blocks: backfill must not be negative and max_rows must be positive")
+	}
+	_, err := c.startProfile()
+	return err
+}
+
+// chains returns the built in profiles extended by the configured ones
+func (c *Config) chains() map[string]util.Chain {
+	chains := util.DefaultChains()
+	for name, chain := range c.Chains {
+		chain.Name = name
+		chains[name] = chains[name].Merge(chain)
+	}
+	// the top level key applies to profiles without their own
+	for name, chain := range chains {
+		if chain.API.Key == "" {
+			chain.API.Key = c.EtherscanKey
+			chains[name] = chain
+		}
+	}
+	return chains
+}
+
+// profile returns the named chain profile
+func (c *Config) profile(name string) (util.Chain, error) {
+	chain, ok := c.chains()[name]
+	if !ok {
+		return util.Chain{}, fmt.Errorf("no chain profile %q configured", name)
+	}
+	return chain, nil
+}
+
+// profileNames lists the chain profiles which can be switched to
+func (c *Config) profileNames() []string {
+	var names []string
+	for name, chain := range c.chains() {
+		if chain.RpcUrl != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// profileByID returns the profile of a chain id, preferring configured
+// profiles over the built in ones. Unknown chains get a bare profile.
+func (c *Config) profileByID(id uint64) util.Chain {
+	chains := c.chains()
+	configured := make(map[string]util.Chain)
+	for name := range c.Chains {
+		configured[name] = chains[name]
+	}
+	if chain, ok := util.ChainByID(configured, id); ok {
+		return chain
+	}
+	if chain, ok := util.ChainByID(chains, id); ok {
+		return chain
+	}
+	return util.Chain{Name: fmt.Sprintf("chain %d", id), ChainID: id, API: util.ContractAPI{Key: c.EtherscanKey}}
+}
+
+// startProfile returns the profile to connect to on startup. Its chain id
+// may not be known until connected.
+func (c *Config) startProfile() (util.Chain, error) {
+	var chain util.Chain
+	if c.Chain != "" {
+		var err error
+		if chain, err = c.profile(c.Chain); err != nil {
+			return chain, err
+		}
+	}
+	if chain.RpcUrl == "" {
+		chain.RpcUrl = c.RpcUrl
+	}
+	if chain.RpcUrl == "" {
+		return chain, fmt.Errorf("no rpc_url configured")
+	}
+	return chain, nil
 }
@@ -3,11 +3,11 @@ package ui
 import (
 	"encoding/hex"
 	"fmt"
-	"os"
-	"os/exec"
+	"sort"
 
 	"code.rocketnine.space/tslocum/cbind"
 	"code.rocketnine.space/tslocum/cview"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gdamore/tcell/v2"
 	"github.com/treethought/ethscan/util"
@@ -38,26 +38,56 @@ func NewContractForm(app *App, address *common.Address) *ContractForm {
 func (c *ContractForm) initBindings() {
 	c.bindings = cbind.NewConfiguration()
 	c.SetInputCapture(c.bindings.Capture)
-	c.bindings.SetRune(tcell.ModNone, 's', c.showSource)
+	c.app.keys.bind(c.bindings, ActionShowSource, c.showSource)
+	c.app.keys.bind(c.bindings, ActionShowStorage, c.showStorage)
+	c.app.keys.bind(c.bindings, ActionShowBytecode, c.showBytecode)
+}
+
+func (c *ContractForm) showBytecode(ev *tcell.EventKey) *tcell.EventKey {
+	if c.address == nil {
+		return nil
+	}
+	c.app.app.SetRoot(NewBytecodeView(c.app, *c.address), true)
+	return nil
 }
 
 func (c *ContractForm) showSource(ev *tcell.EventKey) *tcell.EventKey {
+	if c.sourceView == nil {
+		return nil
+	}
 	c.app.app.SetRoot(c.sourceView, true)
 	return nil
 }
 
+func (c *ContractForm) showStorage(ev *tcell.EventKey) *tcell.EventKey {
+	if c.address == nil {
+		return nil
+	}
+	c.app.app.SetRoot(NewStorageView(c.app, *c.address), true)
+	return nil
+}
+
 func (c *ContractForm) Update() {
+	addr := c.app.State.contractAddress
+	if addr != nil && c.address != nil && *addr == *c.address && c.contract != nil {
+		// already showing this contract, e.g. navigating back to it
+		return
+	}
 
-	c.address = c.app.State.contractAddress
+	c.address = addr
 	if c.address == nil {
 		c.app.log.Error("no contract address set")
 		c.app.app.QueueUpdateDraw(c.render)
 		return
 	}
 
-	contract, err := util.GetContractData(c.address.String(), c.app.config.EtherscanKey)
+	contract, err := util.GetContractData(c.address.String(), c.app.chain.API)
 	if err != nil {
-		c.app.log.Error("failed to get contract data: %v", err)
+		// without verified source fall back to the bytecode
+		c.app.log.Error("failed to get contract data: ", err)
+		c.contract = nil
+		c.sourceView = nil
+		c.app.app.QueueUpdateDraw(c.render)
 		return
 	}
 	c.contract = contract
@@ -99,18 +129,49 @@ func contractABI(c *ContractForm) *cview.List {
 	cabi := c.contract.ABI
 
 	abiInfo := cview.NewList()
-	abiInfo.SetTitle("ABI (hit `s` to view source code)")
+	abiInfo.SetTitle(fmt.Sprintf("ABI (hit `%s` to view source code, enter to call or simulate methods)", c.app.keys.Keys(ActionShowSource)))
 	abiInfo.SetBorder(true)
+	abiInfo.ShowSecondaryText(false)
 	abiInfo.AddItem(cview.NewListItem(fmt.Sprint(cabi.Constructor.String())))
 
-	for _, m := range c.contract.ABI.Methods {
-		abiInfo.AddItem(cview.NewListItem(m.String()))
+	names := make([]string, 0, len(cabi.Methods))
+	for name := range cabi.Methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		m := cabi.Methods[name]
+		item := cview.NewListItem(m.String())
+		if m.IsConstant() {
+			item.SetMainText(fmt.Sprintf("%s %s", c.app.theme.Success.Render("read"), m.String()))
+		} else {
+			item.SetMainText(fmt.Sprintf("%s %s", c.app.theme.Warning.Render("simulate"), m.String()))
+		}
+		item.SetReference(&m)
+		abiInfo.AddItem(item)
 	}
 
+	abiInfo.SetSelectedFunc(func(_ int, item *cview.ListItem) {
+		m, ok := item.GetReference().(*abi.Method)
+		if !ok {
+			return
+		}
+		c.showMethod(m)
+	})
+
 	abiInfo.SetInputCapture(c.bindings.Capture)
 	return abiInfo
 }
 
+func (c *ContractForm) showMethod(m *abi.Method) {
+	if c.address == nil {
+		return
+	}
+	view := NewMethodCallView(c.app, *c.address, c.contract.ABI, m)
+	c.app.app.SetRoot(view, true)
+}
+
 func contractConstructorArgs(c *ContractForm) *cview.List {
 	consArgs := cview.NewList()
 	consArgs.SetBorder(true)
@@ -137,7 +198,8 @@ func contractConstructorArgs(c *ContractForm) *cview.List {
 
 func (c *ContractForm) render() {
 	c.Clear()
-	c.SetTitle("Contract")
+	c.SetTitle(fmt.Sprintf("Contract (hit `%s` to inspect storage, `%s` for bytecode)",
+		c.app.keys.Keys(ActionShowStorage), c.app.keys.Keys(ActionShowBytecode)))
 	c.SetBorder(true)
 	c.SetBorders(true)
 
@@ -145,6 +207,9 @@ func (c *ContractForm) render() {
 	c.SetColumns(0, 0)
 
 	if c.contract == nil {
+		if c.address != nil {
+			c.AddItem(NewBytecodeView(c.app, *c.address), 0, 0, 4, 2, 0, 0, true)
+		}
 		return
 	}
 
@@ -166,78 +231,3 @@ func (c *ContractForm) render() {
 	c.SetBackgroundColor(tcell.ColorDefault)
 
 }
-
-type SourceCodeView struct {
-	*cview.TextView
-	app      *App
-	bindings *cbind.Configuration
-	contract *util.ContractData
-}
-
-func NewSourceCodeView(app *App, contract *util.ContractData) *SourceCodeView {
-	c := &SourceCodeView{
-		TextView: cview.NewTextView(),
-		app:      app,
-		contract: contract,
-	}
-
-	if c.contract != nil {
-		c.SetTitle(c.contract.ContractName)
-		c.SetBorder(true)
-		c.SetText(c.contract.SourceCode)
-	}
-
-	c.initBindings()
-	c.SetDoneFunc(func(_ tcell.Key) {
-		c.app.app.SetRoot(c.app.root, true)
-	})
-	return c
-}
-
-func (s *SourceCodeView) initBindings() {
-	s.bindings = cbind.NewConfiguration()
-	s.bindings.SetKey(tcell.ModNone, tcell.KeyEsc, s.onDone)
-	s.bindings.SetRune(tcell.ModNone, 'e', s.openEditor)
-	s.SetInputCapture(s.bindings.Capture)
-}
-
-func (s *SourceCodeView) onDone(ev *tcell.EventKey) *tcell.EventKey {
-	s.app.app.SetRoot(s.app.root, true)
-	return nil
-}
-
-func (s *SourceCodeView) openEditor(ev *tcell.EventKey) *tcell.EventKey {
-	if s.contract == nil {
-		return nil
-	}
-	tmp, err := os.CreateTemp("", "ethscan*.sol")
-	if err != nil {
-		s.app.log.Error("failed to create tmp file: ", err)
-		return nil
-	}
-	_, err = tmp.Write([]byte(s.contract.SourceCode))
-	if err != nil {
-		s.app.log.Error("failed to write contract: ", err)
-		return nil
-	}
-	tmp.Close()
-
-	s.app.app.Suspend(func() {
-		editor := os.Getenv("EDITOR")
-		path, err := exec.LookPath(editor)
-		if err != nil {
-			s.app.log.Error("failed to lookup $EDITOR")
-		}
-
-		cmd := exec.Command(path, tmp.Name())
-		cmd.Stdin = os.Stdin
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		err = cmd.Run()
-		if err != nil {
-			s.app.log.Error("failed to open editor: ", err)
-			return
-		}
-	})
-	return nil
-}
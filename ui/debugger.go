@@ -0,0 +1,262 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/treethought/ethscan/util"
+)
+
+// DebuggerView steps through the opcodes executed by a transaction.
+type DebuggerView struct {
+	*cview.Flex
+	app      *App
+	txn      *types.Transaction
+	block    *types.Block
+	trace    *util.StepTrace
+	mapper   *util.SourceMapper
+	bindings *cbind.Configuration
+
+	steps   *cview.Table
+	stack   *cview.TextView
+	memory  *cview.TextView
+	storage *cview.TextView
+	right   *cview.Flex
+	source  *SourceCodeView
+}
+
+func NewDebuggerView(app *App, block *types.Block, txn *types.Transaction) *DebuggerView {
+	v := &DebuggerView{
+		Flex:    cview.NewFlex(),
+		app:     app,
+		txn:     txn,
+		block:   block,
+		steps:   cview.NewTable(),
+		stack:   cview.NewTextView(),
+		memory:  cview.NewTextView(),
+		storage: cview.NewTextView(),
+		right:   cview.NewFlex(),
+	}
+
+	v.steps.SetTitle(fmt.Sprintf("Debug %s (loading trace...)", app.client.TxHash(txn).Hex()))
+	v.steps.SetBorder(true)
+	v.steps.SetFixed(1, 0)
+	v.steps.SetSelectable(true, false)
+	v.steps.SetSelectedStyle(app.theme.Selected.tcell())
+	v.steps.SetSelectionChangedFunc(func(row, _ int) {
+		v.showStep(row - 1)
+	})
+
+	v.stack.SetTitle("Stack")
+	v.memory.SetTitle("Memory")
+	v.storage.SetTitle("Storage")
+	for _, tv := range []*cview.TextView{v.stack, v.memory, v.storage} {
+		tv.SetBorder(true)
+		tv.SetDynamicColors(true)
+		tv.SetScrollable(true)
+	}
+
+	v.right.SetDirection(cview.FlexRow)
+	v.right.AddItem(v.stack, 0, 1, false)
+	v.right.AddItem(v.memory, 0, 1, false)
+	v.right.AddItem(v.storage, 0, 1, false)
+
+	v.SetDirection(cview.FlexColumn)
+	v.AddItem(v.steps, 0, 1, true)
+	v.AddItem(v.right, 0, 1, false)
+
+	v.initBindings()
+	go v.load()
+	return v
+}
+
+func (v *DebuggerView) initBindings() {
+	v.bindings = cbind.NewConfiguration()
+	v.app.keys.bind(v.bindings, ActionBack, v.onDone)
+	v.app.keys.bind(v.bindings, ActionHelp, v.app.helpHandler("debugger", v))
+	v.app.keys.bind(v.bindings, ActionNextCall, v.jumper(true, util.StructLog.IsCall))
+	v.app.keys.bind(v.bindings, ActionPrevCall, v.jumper(false, util.StructLog.IsCall))
+	v.app.keys.bind(v.bindings, ActionNextSstore, v.jumper(true, isOp("SSTORE")))
+	v.app.keys.bind(v.bindings, ActionPrevSstore, v.jumper(false, isOp("SSTORE")))
+	v.app.keys.bind(v.bindings, ActionNextRevert, v.jumper(true, isOp("REVERT")))
+	v.app.keys.bind(v.bindings, ActionPrevRevert, v.jumper(false, isOp("REVERT")))
+	v.steps.SetInputCapture(v.bindings.Capture)
+}
+
+func (v *DebuggerView) onDone(ev *tcell.EventKey) *tcell.EventKey {
+	v.app.app.SetRoot(v.app.layout, true)
+	return nil
+}
+
+func isOp(op string) func(util.StructLog) bool {
+	return func(l util.StructLog) bool {
+		return l.Op == op
+	}
+}
+
+// jumper returns a handler selecting the next, or previous, step matching
+// the predicate
+func (v *DebuggerView) jumper(forward bool, match func(util.StructLog) bool) func(*tcell.EventKey) *tcell.EventKey {
+	return func(ev *tcell.EventKey) *tcell.EventKey {
+		if v.trace == nil {
+			return nil
+		}
+		row, _ := v.steps.GetSelection()
+		i := row - 1
+		for {
+			if forward {
+				i++
+			} else {
+				i--
+			}
+			if i < 0 || i >= len(v.trace.StructLogs) {
+				return nil
+			}
+			if match(v.trace.StructLogs[i]) {
+				v.steps.Select(i+1, 0)
+				return nil
+			}
+		}
+	}
+}
+
+func (v *DebuggerView) load() {
+	ctx := context.TODO()
+	trace, err := util.TraceTransactionSteps(ctx, v.app.rpc, v.app.client.TxHash(v.txn), v.txn.To())
+	if err != nil {
+		v.app.log.Error("failed to trace txn: ", err)
+		v.app.app.QueueUpdateDraw(func() {
+			v.steps.SetTitle(fmt.Sprintf("%s %s", v.app.theme.Failure.Render("failed to trace transaction:"), cview.Escape(err.Error())))
+		})
+		return
+	}
+
+	v.app.app.QueueUpdateDraw(func() {
+		v.trace = trace
+		v.renderSteps()
+	})
+
+	v.loadSource(ctx)
+}
+
+// loadSource sets up source highlighting for the called contract when it
+// is verified and a matching compiler is available
+func (v *DebuggerView) loadSource(ctx context.Context) {
+	if v.txn.To() == nil {
+		return
+	}
+	contract, err := util.GetContractData(v.txn.To().String(), v.app.chain.API)
+	if err != nil {
+		v.app.log.Debug("no verified source for debugger: ", err)
+		return
+	}
+	code, err := v.app.client.CodeAt(ctx, *v.txn.To(), v.block.Number())
+	if err != nil {
+		v.app.log.Error("failed to get code: ", err)
+		return
+	}
+	mapper, err := util.NewSourceMapper(ctx, contract, code)
+	if err != nil {
+		v.app.log.Info("source mapping unavailable: ", err)
+		return
+	}
+
+	v.app.app.QueueUpdateDraw(func() {
+		v.mapper = mapper
+		v.source = NewSourceCodeView(v.app, contract)
+		v.right.AddItem(v.source, 0, 2, false)
+		row, _ := v.steps.GetSelection()
+		v.showStep(row - 1)
+	})
+}
+
+func (v *DebuggerView) renderSteps() {
+	status := "success"
+	if v.trace.Failed {
+		status = v.app.theme.Failure.Render("failed")
+	}
+	v.steps.SetTitle(fmt.Sprintf("Debug %s (%d steps, %s) %s/%s/%s: next call/sstore/revert",
+		v.app.client.TxHash(v.txn).Hex(), len(v.trace.StructLogs), status, v.app.keys.Keys(ActionNextCall),
+		v.app.keys.Keys(ActionNextSstore), v.app.keys.Keys(ActionNextRevert)))
+
+	for col, h := range []string{"Step", "PC", "Op", "Gas", "Cost", "Depth"} {
+		cell := cview.NewTableCell(h)
+		cell.SetSelectable(false)
+		v.steps.SetCell(0, col, cell)
+	}
+	for i, l := range v.trace.StructLogs {
+		row := i + 1
+		op := l.Op
+		switch {
+		case l.Op == "REVERT" || l.Error != "":
+			op = v.app.theme.Failure.Render(l.Op)
+		case l.IsCall():
+			op = v.app.theme.Contract.Render(l.Op)
+		case l.Op == "SSTORE":
+			op = v.app.theme.Highlight.Render(l.Op)
+		}
+		v.steps.SetCell(row, 0, cview.NewTableCell(fmt.Sprint(i)))
+		v.steps.SetCell(row, 1, cview.NewTableCell(fmt.Sprintf("0x%04x", l.PC)))
+		v.steps.SetCell(row, 2, cview.NewTableCell(op))
+		v.steps.SetCell(row, 3, cview.NewTableCell(fmt.Sprint(l.Gas)))
+		v.steps.SetCell(row, 4, cview.NewTableCell(fmt.Sprint(l.GasCost)))
+		v.steps.SetCell(row, 5, cview.NewTableCell(fmt.Sprint(l.Depth)))
+	}
+	if len(v.trace.StructLogs) > 0 {
+		v.steps.Select(1, 0)
+	}
+}
+
+func (v *DebuggerView) showStep(i int) {
+	if v.trace == nil || i < 0 || i >= len(v.trace.StructLogs) {
+		return
+	}
+	l := v.trace.StructLogs[i]
+
+	var stack strings.Builder
+	for j := len(l.Stack) - 1; j >= 0; j-- {
+		fmt.Fprintf(&stack, "%2d: %s\n", len(l.Stack)-1-j, util.HexStripZeros(l.Stack[j]))
+	}
+	v.stack.SetText(stack.String())
+
+	var memory strings.Builder
+	for j, word := range l.Memory {
+		fmt.Fprintf(&memory, "0x%04x: %s\n", j*32, word)
+	}
+	v.memory.SetText(memory.String())
+
+	var prev map[string]string
+	if i > 0 && v.trace.StructLogs[i-1].Depth == l.Depth {
+		prev = v.trace.StructLogs[i-1].Storage
+	}
+	slots := make([]string, 0, len(l.Storage))
+	for slot := range l.Storage {
+		slots = append(slots, slot)
+	}
+	sort.Strings(slots)
+	var storage strings.Builder
+	for _, slot := range slots {
+		val := l.Storage[slot]
+		if prev != nil && prev[slot] != val {
+			fmt.Fprintf(&storage, "%s\n", v.app.theme.Highlight.Render(fmt.Sprintf("%s: %s", util.HexStripZeros(slot), util.HexStripZeros(val))))
+			continue
+		}
+		fmt.Fprintf(&storage, "%s: %s\n", util.HexStripZeros(slot), util.HexStripZeros(val))
+	}
+	if l.Error != "" {
+		fmt.Fprintf(&storage, "\n%s\n", v.app.theme.Failure.Render("error: "+cview.Escape(l.Error)))
+	}
+	v.storage.SetText(storage.String())
+
+	if v.mapper != nil && v.source != nil && l.CodeAddress != nil && *l.CodeAddress == *v.txn.To() {
+		if path, line, ok := v.mapper.Locate(l.PC); ok {
+			v.source.ShowLocation(path, line)
+		}
+	}
+}
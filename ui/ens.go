@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/atotto/clipboard"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gdamore/tcell/v2"
+	"github.com/treethought/ethscan/util"
+)
+
+// expiryWarning is how long before expiring a name is shown as expiring
+const expiryWarning = 30 * 24 * time.Hour
+
+// ENSView shows the registration and records of an ENS name.
+type ENSView struct {
+	*cview.TextView
+	app      *App
+	name     string
+	record   *util.ENSRecord
+	bindings *cbind.Configuration
+}
+
+func NewENSView(app *App, name string) *ENSView {
+	v := &ENSView{
+		TextView: cview.NewTextView(),
+		app:      app,
+		name:     name,
+	}
+	v.SetTitle(fmt.Sprintf("ENS %s (hit `%s` to copy the address)", name, app.keys.Keys(ActionCopy)))
+	v.SetBorder(true)
+	v.SetDynamicColors(true)
+	v.SetScrollable(true)
+	v.SetWordWrap(true)
+	v.SetText("Loading...")
+
+	v.bindings = cbind.NewConfiguration()
+	app.keys.bind(v.bindings, ActionBack, v.onDone)
+	app.keys.bind(v.bindings, ActionHelp, app.helpHandler("ens", v))
+	app.keys.bind(v.bindings, ActionCopy, v.handleCopy)
+	v.SetInputCapture(v.bindings.Capture)
+
+	go v.load()
+	return v
+}
+
+func (v *ENSView) onDone(ev *tcell.EventKey) *tcell.EventKey {
+	v.app.app.SetRoot(v.app.layout, true)
+	return nil
+}
+
+func (v *ENSView) handleCopy(ev *tcell.EventKey) *tcell.EventKey {
+	if v.record == nil || v.record.Address == (common.Address{}) {
+		return nil
+	}
+	if err := clipboard.WriteAll(v.record.Address.Hex()); err != nil {
+		v.app.notifyError("failed to copy", err)
+		return nil
+	}
+	v.app.notify(fmt.Sprintf("copied address of %s", v.record.Name))
+	return nil
+}
+
+func (v *ENSView) load() {
+	rec, err := v.app.ens.Lookup(v.app.ctx, v.name)
+	v.app.app.QueueUpdateDraw(func() {
+		if err != nil {
+			v.SetText(fmt.Sprintf("%s %s", v.app.theme.Failure.Render("failed to look up name:"), cview.Escape(err.Error())))
+			return
+		}
+		v.record = rec
+		v.SetText(v.render(rec))
+	})
+}
+
+func (v *ENSView) render(rec *util.ENSRecord) string {
+	var b strings.Builder
+	addr := func(a common.Address) string {
+		if a == (common.Address{}) {
+			return v.app.theme.Muted.Render("none")
+		}
+		return a.Hex()
+	}
+	field := func(name, value string) {
+		fmt.Fprintf(&b, "%s %s\n", v.app.theme.Keyword.Render(name+":"), value)
+	}
+
+	field("Name", v.app.theme.ENS.Render(cview.Escape(rec.Name)))
+	field("Address", addr(rec.Address))
+	field("Owner", addr(rec.Owner))
+	if rec.Registrant != (common.Address{}) {
+		field("Registrant", addr(rec.Registrant))
+	}
+	field("Resolver", addr(rec.Resolver))
+	if !rec.Expires.IsZero() {
+		expires := util.FormatTime(rec.Expires)
+		switch left := time.Until(rec.Expires); {
+		case left < 0:
+			expires = v.app.theme.Failure.Render(expires + " (expired)")
+		case left < expiryWarning:
+			expires = v.app.theme.Warning.Render(fmt.Sprintf("%s (in %d days)", expires, int(left.Hours()/24)))
+		}
+		field("Expires", expires)
+	}
+	if rec.Contenthash != "" {
+		field("Content Hash", cview.Escape(rec.Contenthash))
+	}
+
+	b.WriteString("\n")
+	if len(rec.Texts) == 0 {
+		b.WriteString(v.app.theme.Muted.Render("no text records"))
+		return b.String()
+	}
+	b.WriteString(v.app.theme.Keyword.Render("Text Records") + "\n")
+	for _, key := range util.ENSTextKeys {
+		if text, ok := rec.Texts[key]; ok {
+			fmt.Fprintf(&b, "  %s: %s\n", key, cview.Escape(text))
+		}
+	}
+	return b.String()
+}
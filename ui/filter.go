@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// filterField describes a field which filter conditions may refer to
+type filterField struct {
+	numeric bool
+	// percent fields accept values such as `90%`
+	percent bool
+}
+
+// filterValue is the value of a field in a row
+type filterValue struct {
+	num   float64
+	isNum bool
+	// pct is the value as a percentage, for percent fields
+	pct float64
+	// texts are matched by = and != as case insensitive substrings, e.g.
+	// an address along with its label
+	texts []string
+}
+
+func numValue(n float64) filterValue {
+	return filterValue{num: n, isNum: true, texts: []string{strconv.FormatFloat(n, 'f', -1, 64)}}
+}
+
+func textValue(texts ...string) filterValue {
+	return filterValue{texts: texts}
+}
+
+// less orders values for sorting, numbers before text
+func (v filterValue) less(o filterValue) bool {
+	if v.isNum && o.isNum {
+		return v.num < o.num
+	}
+	if v.isNum != o.isNum {
+		return v.isNum
+	}
+	return strings.ToLower(first(v.texts)) < strings.ToLower(first(o.texts))
+}
+
+func first(s []string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	return s[0]
+}
+
+type condition struct {
+	field   string
+	op      string
+	text    string
+	numeric bool
+	num     float64
+	percent bool
+}
+
+// filter is a list of conditions which must all match
+type filter []condition
+
+var conditionRe = regexp.MustCompile(`^\s*(\w+)\s*(>=|<=|!=|=|>|<)\s*(.+?)\s*$`)
+
+var conditionSep = regexp.MustCompile(`(?i)\s+and\s+|,`)
+
+// parseFilter parses expressions such as `gasUsed > 90%, miner = flashbots`.
+// Field names are case insensitive.
+func parseFilter(expr string, fields map[string]filterField) (filter, error) {
+	var f filter
+	if strings.TrimSpace(expr) == "" {
+		return f, nil
+	}
+	for _, part := range conditionSep.Split(expr, -1) {
+		m := conditionRe.FindStringSubmatch(part)
+		if m == nil {
+			return nil, fmt.Errorf("invalid condition %q, expected <field> <op> <value>", strings.TrimSpace(part))
+		}
+		name, field, ok := lookupField(m[1], fields)
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q, available: %s", m[1], strings.Join(fieldNames(fields), ", "))
+		}
+		c := condition{field: name, op: m[2], text: strings.ToLower(m[3])}
+
+		if !field.numeric {
+			if c.op != "=" && c.op != "!=" {
+				return nil, fmt.Errorf("%s only supports = and !=", name)
+			}
+			f = append(f, c)
+			continue
+		}
+
+		val := m[3]
+		if strings.HasSuffix(val, "%") {
+			if !field.percent {
+				return nil, fmt.Errorf("%s is not a percentage", name)
+			}
+			c.percent = true
+			val = strings.TrimSuffix(val, "%")
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s needs a number, got %q", name, m[3])
+		}
+		c.numeric = true
+		c.num = n
+		f = append(f, c)
+	}
+	return f, nil
+}
+
+func lookupField(name string, fields map[string]filterField) (string, filterField, bool) {
+	for n, f := range fields {
+		if strings.EqualFold(n, name) {
+			return n, f, true
+		}
+	}
+	return "", filterField{}, false
+}
+
+func fieldNames(fields map[string]filterField) []string {
+	names := make([]string, 0, len(fields))
+	for n := range fields {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// match reports whether the row, given as a lookup of its fields,
+// satisfies every condition
+func (f filter) match(value func(field string) filterValue) bool {
+	for _, c := range f {
+		if !c.match(value(c.field)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c condition) match(v filterValue) bool {
+	if !c.numeric {
+		found := false
+		for _, t := range v.texts {
+			if strings.Contains(strings.ToLower(t), c.text) {
+				found = true
+				break
+			}
+		}
+		return found == (c.op == "=")
+	}
+	if !v.isNum {
+		return false
+	}
+
+	n := v.num
+	if c.percent {
+		n = v.pct
+	}
+	switch c.op {
+	case "=":
+		return n == c.num
+	case "!=":
+		return n != c.num
+	case ">":
+		return n > c.num
+	case ">=":
+		return n >= c.num
+	case "<":
+		return n < c.num
+	case "<=":
+		return n <= c.num
+	}
+	return false
+}
+
+// Filterable views narrow and order their rows, see the filter and sort
+// commands
+type Filterable interface {
+	Filter() string
+	SetFilter(expr string) error
+	SortBy(column string, desc bool) error
+	SortColumns() []string
+}
+
+// Columnar views have configurable columns
+type Columnar interface {
+	Columns() []string
+	SetColumns(names []string) error
+}
@@ -0,0 +1,91 @@
+package ui
+
+import "testing"
+
+func TestParseFilter(t *testing.T) {
+	fields := map[string]filterField{
+		"gasUsed": {numeric: true, percent: true},
+		"txns":    {numeric: true},
+		"miner":   {},
+	}
+
+	tests := []struct {
+		expr    string
+		want    int
+		wantErr bool
+	}{
+		{expr: ""},
+		{expr: "txns > 10", want: 1},
+		{expr: "GASUSED>=90%, miner = flashbots", want: 2},
+		{expr: "txns <= 5 AND txns != 0 and miner != beaver", want: 3},
+		{expr: "txns", wantErr: true},
+		{expr: "difficulty > 1", wantErr: true},
+		{expr: "miner > a", wantErr: true},
+		{expr: "txns > 10%", wantErr: true},
+		{expr: "txns > many", wantErr: true},
+		{expr: "txns > 1,", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := parseFilter(tt.expr, fields)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseFilter = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFilter: %v", err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("parseFilter = %+v, want %d conditions", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	fields := map[string]filterField{
+		"gasUsed": {numeric: true, percent: true},
+		"txns":    {numeric: true},
+		"miner":   {},
+	}
+	gasUsed := numValue(27_000_000)
+	gasUsed.pct = 90
+	row := map[string]filterValue{
+		"gasUsed": gasUsed,
+		"txns":    numValue(120),
+		"miner":   textValue("0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5", "beaverbuild"),
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{expr: "", want: true},
+		{expr: "txns = 120", want: true},
+		{expr: "txns > 120"},
+		{expr: "txns >= 120, txns < 121", want: true},
+		{expr: "gasUsed >= 90%", want: true},
+		{expr: "gasUsed > 90%"},
+		{expr: "gasUsed > 26000000", want: true},
+		{expr: "miner = Beaver", want: true},
+		{expr: "miner = 0x9522", want: true},
+		{expr: "miner != beaver"},
+		{expr: "miner != titan", want: true},
+		{expr: "txns > 100 and miner = titan"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := parseFilter(tt.expr, fields)
+			if err != nil {
+				t.Fatalf("parseFilter: %v", err)
+			}
+			if got := f.match(func(field string) filterValue { return row[field] }); got != tt.want {
+				t.Errorf("match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/treethought/ethscan/util"
+)
+
+// gasHistoryBlocks is the number of blocks of fee history shown
+const gasHistoryBlocks = 40
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+func minMax(values []float64) (float64, float64) {
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	return min, max
+}
+
+// sparkline draws the values scaled between their min and max
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	min, max := minMax(values)
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if max > min {
+			i = int((v - min) / (max - min) * float64(len(sparks)-1))
+		}
+		b.WriteRune(sparks[i])
+	}
+	return b.String()
+}
+
+func gweiFloat(wei *big.Int) float64 {
+	if wei == nil {
+		return 0
+	}
+	return bigFloat(util.WeiToGwei(wei))
+}
+
+// GasView tracks fees over the recent blocks and suggests fees for the
+// next one.
+type GasView struct {
+	*cview.Flex
+	app       *App
+	bindings  *cbind.Configuration
+	started   bool
+	baseFee   *cview.TextView
+	gasUsed   *cview.TextView
+	rewards   *cview.Table
+	estimates *cview.TextView
+	// refreshing is sent to while a fee history request is in flight
+	refreshing chan struct{}
+}
+
+func NewGasView(app *App) *GasView {
+	v := &GasView{
+		Flex:       cview.NewFlex(),
+		app:        app,
+		baseFee:    cview.NewTextView(),
+		gasUsed:    cview.NewTextView(),
+		rewards:    cview.NewTable(),
+		estimates:  cview.NewTextView(),
+		refreshing: make(chan struct{}, 1),
+	}
+	v.baseFee.SetTitle("Base Fee (Gwei)")
+	v.gasUsed.SetTitle("Gas Used")
+	v.rewards.SetTitle("Priority Fees (Gwei)")
+	v.estimates.SetTitle("Next Block")
+	for _, tv := range []*cview.TextView{v.baseFee, v.gasUsed, v.estimates} {
+		tv.SetBorder(true)
+		tv.SetDynamicColors(true)
+	}
+	v.rewards.SetBorder(true)
+	v.rewards.SetFixed(1, 0)
+	v.rewards.SetSelectable(true, false)
+	v.rewards.SetSelectedStyle(app.theme.Selected.tcell())
+
+	charts := cview.NewFlex()
+	charts.AddItem(v.baseFee, 0, 1, false)
+	charts.AddItem(v.gasUsed, 0, 1, false)
+
+	bottom := cview.NewFlex()
+	bottom.AddItem(v.rewards, 0, 2, true)
+	bottom.AddItem(v.estimates, 0, 1, false)
+
+	v.SetDirection(cview.FlexRow)
+	v.AddItem(charts, 7, 0, false)
+	v.AddItem(bottom, 0, 1, true)
+
+	v.bindings = cbind.NewConfiguration()
+	app.keys.bind(v.bindings, ActionRefresh, v.handleRefresh)
+	v.SetInputCapture(v.bindings.Capture)
+	return v
+}
+
+// Update starts following new blocks the first time the view is shown
+func (v *GasView) Update() {
+	if v.started {
+		return
+	}
+	v.started = true
+	go v.watch(v.app.ctx)
+}
+
+func (v *GasView) handleRefresh(ev *tcell.EventKey) *tcell.EventKey {
+	go v.refresh(v.app.ctx, nil)
+	return nil
+}
+
+func (v *GasView) watch(ctx context.Context) {
+	headers := v.app.broker.SubscribeHeaders()
+	go v.refresh(ctx, nil)
+	for {
+		select {
+		case h := <-headers:
+			go v.refresh(ctx, h)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refresh loads the fee history up to the header, or the latest block when
+// nil. Blocks arriving during a refresh are skipped.
+func (v *GasView) refresh(ctx context.Context, head *types.Header) {
+	select {
+	case v.refreshing <- struct{}{}:
+		defer func() { <-v.refreshing }()
+	default:
+		return
+	}
+
+	var err error
+	if head == nil {
+		head, err = v.app.client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			v.app.notifyError("failed to get latest block", err)
+			return
+		}
+	}
+	history, err := v.app.client.FeeHistory(ctx, gasHistoryBlocks, head.Number, util.FeePercentiles)
+	if err != nil {
+		v.app.notifyError("failed to get fee history", err)
+		return
+	}
+	v.app.app.QueueUpdateDraw(func() {
+		v.render(head, history)
+	})
+}
+
+func (v *GasView) render(head *types.Header, history *ethereum.FeeHistory) {
+	// the history has the base fee of the next block as well
+	baseFees := history.BaseFee
+	if len(baseFees) > len(history.GasUsedRatio) {
+		baseFees = baseFees[:len(history.GasUsedRatio)]
+	}
+
+	fees := make([]float64, len(baseFees))
+	for i, f := range baseFees {
+		fees[i] = gweiFloat(f)
+	}
+	ratios := make([]float64, len(history.GasUsedRatio))
+	for i, r := range history.GasUsedRatio {
+		ratios[i] = r * 100
+	}
+	v.baseFee.SetText(v.chart(fees, "%.2f"))
+	v.gasUsed.SetText(v.chart(ratios, "%.1f%%"))
+	v.baseFee.SetTitle(fmt.Sprintf("Base Fee (Gwei), last %d blocks", len(fees)))
+	v.gasUsed.SetTitle(fmt.Sprintf("Gas Used, last %d blocks", len(ratios)))
+
+	v.renderRewards(history, baseFees)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Block: #%d\n", new(big.Int).Add(head.Number, big.NewInt(1)))
+	if head.BaseFee == nil {
+		fmt.Fprintf(&b, "Base Fee: %.2f Gwei\n\n", gweiFloat(nil))
+		b.WriteString(v.app.theme.Muted.Render("the chain has no base fee"))
+		v.estimates.SetText(b.String())
+		return
+	}
+	next, source := v.nextBaseFee(head, history)
+	if next == nil {
+		b.WriteString("Base Fee: unknown\n\n")
+		b.WriteString(v.app.theme.Muted.Render(fmt.Sprintf("no EIP-1559 parameters for %s, set its fee_market", v.app.chain.Name)))
+		v.estimates.SetText(b.String())
+		return
+	}
+	fmt.Fprintf(&b, "Base Fee: %.2f Gwei%s\n\n", gweiFloat(next), source)
+	styles := []Style{v.app.theme.Muted, "", v.app.theme.Highlight}
+	for i, est := range util.EstimateFees(history, next) {
+		name := []string{"Slow", "Standard", "Fast"}[i]
+		fmt.Fprintf(&b, "%s\n  max fee %.2f | priority %.2f\n", styles[i].Render(name),
+			gweiFloat(est.MaxFee), gweiFloat(est.MaxPriorityFee))
+	}
+	v.estimates.SetText(b.String())
+}
+
+// nextBaseFee predicts the base fee of the next block with the EIP-1559
+// parameters of the chain, or else takes the one the node returned with the
+// fee history, labelled as such. It is nil when neither is known.
+func (v *GasView) nextBaseFee(head *types.Header, history *ethereum.FeeHistory) (*big.Int, string) {
+	if market := v.app.chain.FeeMarket; market != nil {
+		return util.NextBaseFee(head, *market), ""
+	}
+	if len(history.BaseFee) > len(history.GasUsedRatio) {
+		return history.BaseFee[len(history.GasUsedRatio)], v.app.theme.Muted.Render(" (from the node)")
+	}
+	return nil, ""
+}
+
+// chart draws a sparkline with the latest, min and max values
+func (v *GasView) chart(values []float64, format string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	min, max := minMax(values)
+	stat := func(name string, f float64) string {
+		return fmt.Sprintf("%s "+format, name, f)
+	}
+	return fmt.Sprintf("%s\n\n%s | %s | %s", v.app.theme.Highlight.Render(sparkline(values)),
+		stat("latest", values[len(values)-1]), v.app.theme.Muted.Render(stat("min", min)), v.app.theme.Muted.Render(stat("max", max)))
+}
+
+func (v *GasView) renderRewards(history *ethereum.FeeHistory, baseFees []*big.Int) {
+	v.rewards.Clear()
+	titles := []string{"Block", "Base Fee", "Gas Used"}
+	for _, p := range util.FeePercentiles {
+		titles = append(titles, fmt.Sprintf("p%g", p))
+	}
+	for col, title := range titles {
+		cell := cview.NewTableCell(title)
+		cell.SetSelectable(false)
+		v.rewards.SetCell(0, col, cell)
+	}
+
+	// newest on top
+	row := 1
+	for i := len(history.GasUsedRatio) - 1; i >= 0; i-- {
+		number := new(big.Int).Add(history.OldestBlock, big.NewInt(int64(i)))
+		v.rewards.SetCell(row, 0, cview.NewTableCell(number.String()))
+		if i < len(baseFees) {
+			v.rewards.SetCell(row, 1, cview.NewTableCell(fmt.Sprintf("%.2f", gweiFloat(baseFees[i]))))
+		}
+		v.rewards.SetCell(row, 2, cview.NewTableCell(fmt.Sprintf("%.1f%%", history.GasUsedRatio[i]*100)))
+		if i < len(history.Reward) {
+			for j, r := range history.Reward[i] {
+				v.rewards.SetCell(row, 3+j, cview.NewTableCell(fmt.Sprintf("%.2f", gweiFloat(r))))
+			}
+		}
+		row++
+	}
+}
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"code.rocketnine.space/tslocum/cview"
+)
+
+var solidityKeywords = map[string]bool{}
+
+func init() {
+	for _, k := range strings.Fields(`pragma solidity import from as contract interface library abstract
+		function modifier event struct enum error returns return if else for while do break continue
+		emit new delete public private internal external view pure payable constant immutable
+		override virtual memory storage calldata using is constructor fallback receive require
+		revert assert assembly unchecked try catch indexed anonymous this super true false
+		wei gwei ether seconds minutes hours days weeks type`) {
+		solidityKeywords[k] = true
+	}
+}
+
+var solidityTypeRe = regexp.MustCompile(`^(address|bool|string|byte|mapping|u?int\d*|bytes\d*|u?fixed[\dx]*)$`)
+
+// highlighter colours solidity source line by line, carrying block
+// comment state across lines.
+type highlighter struct {
+	theme     *Theme
+	inComment bool
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// line returns the line with cview colour tags applied, all other text is
+// escaped
+func (h *highlighter) line(src string) string {
+	var out, plain strings.Builder
+	flush := func() {
+		out.WriteString(cview.Escape(plain.String()))
+		plain.Reset()
+	}
+	colored := func(style Style, text string) {
+		flush()
+		out.WriteString(style.Render(cview.Escape(text)))
+	}
+
+	i := 0
+	for i < len(src) {
+		if h.inComment {
+			end := strings.Index(src[i:], "*/")
+			if end < 0 {
+				colored(h.theme.Comment, src[i:])
+				i = len(src)
+				break
+			}
+			colored(h.theme.Comment, src[i:i+end+2])
+			i += end + 2
+			h.inComment = false
+			continue
+		}
+
+		c := src[i]
+		switch {
+		case strings.HasPrefix(src[i:], "//"):
+			colored(h.theme.Comment, src[i:])
+			i = len(src)
+
+		case strings.HasPrefix(src[i:], "/*"):
+			h.inComment = true
+			colored(h.theme.Comment, "/*")
+			i += 2
+
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				j = len(src) - 1
+			}
+			colored(h.theme.String, src[i:j+1])
+			i = j + 1
+
+		case c >= '0' && c <= '9' && (i == 0 || !isIdentChar(src[i-1])):
+			j := i
+			for j < len(src) && (isIdentChar(src[j]) || src[j] == '.') {
+				j++
+			}
+			colored(h.theme.Number, src[i:j])
+			i = j
+
+		case isIdentChar(c):
+			j := i
+			for j < len(src) && isIdentChar(src[j]) {
+				j++
+			}
+			word := src[i:j]
+			switch {
+			case solidityKeywords[word]:
+				colored(h.theme.Keyword, word)
+			case solidityTypeRe.MatchString(word):
+				colored(h.theme.Type, word)
+			default:
+				plain.WriteString(word)
+			}
+			i = j
+
+		default:
+			plain.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+	return out.String()
+}
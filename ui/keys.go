@@ -0,0 +1,293 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Actions which can be bound to keys in the keymap section of the config.
+const (
+	ActionBack             = "back"
+	ActionForward          = "forward"
+	ActionCommand          = "command"
+	ActionSearch           = "search"
+	ActionHelp             = "help"
+	ActionOpenBrowser      = "open-in-browser"
+	ActionCopy             = "copy"
+	ActionShowContract     = "show-contract"
+	ActionShowSource       = "show-source"
+	ActionShowStorage      = "show-storage"
+	ActionShowBytecode     = "show-bytecode"
+	ActionReplay           = "replay"
+	ActionDebug            = "debug"
+	ActionOpenEditor       = "open-editor"
+	ActionToggleFocus      = "toggle-focus"
+	ActionFind             = "find"
+	ActionFindNext         = "find-next"
+	ActionFindPrev         = "find-prev"
+	ActionJumpToDefinition = "jump-to-definition"
+	ActionRefresh          = "refresh"
+	ActionNextCall         = "next-call"
+	ActionPrevCall         = "prev-call"
+	ActionNextSstore       = "next-sstore"
+	ActionPrevSstore       = "prev-sstore"
+	ActionNextRevert       = "next-revert"
+	ActionPrevRevert       = "prev-revert"
+	ActionSort             = "sort"
+	ActionReverseSort      = "reverse-sort"
+	ActionFilter           = "filter"
+	ActionLoadOlder        = "load-older"
+	ActionPause            = "pause"
+	ActionShowBlobs        = "show-blobs"
+	ActionShowENS          = "show-ens"
+)
+
+type actionInfo struct {
+	keys        []string
+	description string
+}
+
+var defaultKeymap = map[string]actionInfo{
+	ActionBack:             {[]string{"Esc", "["}, "go back"},
+	ActionForward:          {[]string{"]"}, "go forward"},
+	ActionCommand:          {[]string{":"}, "open the command bar"},
+	ActionSearch:           {[]string{"/"}, "search for a block, txn, address..."},
+	ActionHelp:             {[]string{"?"}, "show this help"},
+	ActionOpenBrowser:      {[]string{"o"}, "open in the browser"},
+	ActionCopy:             {[]string{"y"}, "copy to the clipboard"},
+	ActionShowContract:     {[]string{"c"}, "show the contract"},
+	ActionShowSource:       {[]string{"s"}, "show the verified source"},
+	ActionShowStorage:      {[]string{"g"}, "inspect storage"},
+	ActionShowBytecode:     {[]string{"b"}, "show the bytecode"},
+	ActionReplay:           {[]string{"r"}, "re-simulate the transaction"},
+	ActionDebug:            {[]string{"d"}, "debug the transaction"},
+	ActionOpenEditor:       {[]string{"e"}, "export and open in $EDITOR"},
+	ActionToggleFocus:      {[]string{"Tab"}, "switch between panes"},
+	ActionFind:             {[]string{"/"}, "find text"},
+	ActionFindNext:         {[]string{"n"}, "next match"},
+	ActionFindPrev:         {[]string{"N"}, "previous match"},
+	ActionJumpToDefinition: {[]string{"d"}, "jump to a definition"},
+	ActionRefresh:          {[]string{"r"}, "refresh"},
+	ActionNextCall:         {[]string{"c"}, "next call"},
+	ActionPrevCall:         {[]string{"C"}, "previous call"},
+	ActionNextSstore:       {[]string{"s"}, "next SSTORE"},
+	ActionPrevSstore:       {[]string{"S"}, "previous SSTORE"},
+	ActionNextRevert:       {[]string{"r"}, "next REVERT"},
+	ActionPrevRevert:       {[]string{"R"}, "previous REVERT"},
+	ActionSort:             {[]string{"s"}, "sort by the next column"},
+	ActionReverseSort:      {[]string{"S"}, "reverse the sort order"},
+	ActionFilter:           {[]string{"f"}, "filter rows"},
+	ActionLoadOlder:        {[]string{"L"}, "load older blocks"},
+	ActionPause:            {[]string{"p"}, "pause or resume live updates"},
+	ActionShowBlobs:        {[]string{"b"}, "fetch the blobs from the beacon api"},
+	ActionShowENS:          {[]string{"e"}, "show the ENS name"},
+}
+
+// scopeActions lists the actions bound in each view. Keys only need to be
+// unique within a scope, tab views share the global scope as well.
+var scopeActions = map[string][]string{
+	"global":       {ActionBack, ActionForward, ActionCommand, ActionSearch, ActionHelp},
+	"blocks":       {ActionOpenBrowser, ActionCopy, ActionSort, ActionReverseSort, ActionFilter, ActionLoadOlder, ActionPause},
+	"transactions": {ActionOpenBrowser, ActionCopy, ActionShowContract, ActionSort, ActionReverseSort, ActionFilter, ActionFind, ActionFindNext, ActionFindPrev},
+	"transaction":  {ActionReplay, ActionDebug, ActionShowBlobs},
+	"contract":     {ActionShowSource, ActionShowStorage, ActionShowBytecode},
+	"address":      {ActionShowContract, ActionShowENS},
+	"mempool":      {ActionCopy},
+	"gas":          {ActionRefresh},
+	"source": {ActionBack, ActionHelp, ActionToggleFocus, ActionOpenEditor,
+		ActionFind, ActionFindNext, ActionFindPrev, ActionJumpToDefinition},
+	"debugger": {ActionBack, ActionHelp, ActionNextCall, ActionPrevCall,
+		ActionNextSstore, ActionPrevSstore, ActionNextRevert, ActionPrevRevert},
+	"bytecode": {ActionBack, ActionHelp},
+	"blobs":    {ActionBack, ActionHelp},
+	"ens":      {ActionBack, ActionHelp, ActionCopy},
+	"log":      {ActionBack, ActionHelp, ActionRefresh},
+	"results":  {ActionBack},
+}
+
+// shadowActions may share a key with a global action, taking its place in
+// the views binding them
+var shadowActions = map[string]bool{
+	ActionFind: true,
+}
+
+// tabScopes maps the tabs to their scope
+var tabScopes = map[string]string{
+	"blockFeed": "blocks",
+	"blockData": "transactions",
+	"txnData":   "transaction",
+	"contract":  "contract",
+	"address":   "address",
+	"mempool":   "mempool",
+	"gas":       "gas",
+}
+
+// Keymap maps actions to the keys which trigger them, in cbind's format
+// such as `o`, `Esc` or `Ctrl+S`. Views with text inputs always close with
+// Esc, as rune bindings would swallow typed text.
+type Keymap map[string][]string
+
+// NewKeymap applies the configured overrides to the default keymap and
+// validates the result.
+func NewKeymap(overrides map[string][]string) (Keymap, error) {
+	k := make(Keymap, len(defaultKeymap))
+	for action, info := range defaultKeymap {
+		k[action] = info.keys
+	}
+	for action, keys := range overrides {
+		if _, ok := defaultKeymap[action]; !ok {
+			return nil, fmt.Errorf("keymap: unknown action %q", action)
+		}
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("keymap: no keys for action %q", action)
+		}
+		k[action] = keys
+	}
+	return k, k.validate()
+}
+
+// validate checks every key decodes and that no two actions of a scope
+// share a key
+func (k Keymap) validate() error {
+	for action, keys := range k {
+		for _, key := range keys {
+			if _, _, _, err := cbind.Decode(key); err != nil {
+				return fmt.Errorf("keymap: invalid key %q for %s", key, action)
+			}
+		}
+	}
+
+	for scope, actions := range scopeActions {
+		if tabScope(scope) {
+			actions = append(append([]string{}, scopeActions["global"]...), actions...)
+		}
+		seen := make(map[string]string)
+		for _, action := range actions {
+			for _, key := range k[action] {
+				id := keyID(key)
+				if other, ok := seen[id]; ok && other != action && !shadows(other, action) {
+					return fmt.Errorf("keymap: %q is bound to both %s and %s in the %s view", key, other, action, scope)
+				}
+				seen[id] = action
+			}
+		}
+	}
+	return nil
+}
+
+// shadows reports whether the scoped action may take the key of the global
+// one
+func shadows(global, action string) bool {
+	for _, g := range scopeActions["global"] {
+		if g == global {
+			return shadowActions[action]
+		}
+	}
+	return false
+}
+
+// shadowed reports whether the key event triggers an action of the scope
+// which takes the place of a global action
+func (k Keymap) shadowed(scope string, ev *tcell.EventKey) bool {
+	c := cbind.NewConfiguration()
+	for _, action := range scopeActions[scope] {
+		if shadowActions[action] {
+			k.bind(c, action, func(ev *tcell.EventKey) *tcell.EventKey { return nil })
+		}
+	}
+	return c.Capture(ev) == nil
+}
+
+func tabScope(scope string) bool {
+	for _, s := range tabScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// keyID normalizes a key so different spellings of the same key compare
+// equal
+func keyID(key string) string {
+	mod, k, ch, _ := cbind.Decode(key)
+	return fmt.Sprintf("%d-%d-%d", mod, k, ch)
+}
+
+// bind sets the handler for every key of the action
+func (k Keymap) bind(c *cbind.Configuration, action string, handler func(ev *tcell.EventKey) *tcell.EventKey) {
+	for _, key := range k[action] {
+		// keys are validated on startup
+		_ = c.Set(key, handler)
+	}
+}
+
+// Keys returns the keys of the action for display
+func (k Keymap) Keys(action string) string {
+	return strings.Join(k[action], ", ")
+}
+
+// help renders the bindings of a scope
+func (k Keymap) help(scope string) string {
+	var b strings.Builder
+	write := func(title string, actions []string) {
+		actions = append([]string{}, actions...)
+		sort.Strings(actions)
+		fmt.Fprintf(&b, "[::b]%s[::-]\n", title)
+		for _, action := range actions {
+			fmt.Fprintf(&b, "  %-12s %-20s %s\n", cview.Escape(k.Keys(action)), action, defaultKeymap[action].description)
+		}
+		b.WriteString("\n")
+	}
+	write(scope, scopeActions[scope])
+	if tabScope(scope) {
+		write("global", scopeActions["global"])
+	}
+	return b.String()
+}
+
+// HelpView lists the key bindings of a view.
+type HelpView struct {
+	*cview.TextView
+	app      *App
+	prev     cview.Primitive
+	bindings *cbind.Configuration
+}
+
+// showHelp shows the bindings of the scope, returning to prev when closed
+func (a *App) showHelp(scope string, prev cview.Primitive) {
+	v := &HelpView{
+		TextView: cview.NewTextView(),
+		app:      a,
+		prev:     prev,
+	}
+	v.SetTitle(fmt.Sprintf("Keys (%s)", scope))
+	v.SetBorder(true)
+	v.SetDynamicColors(true)
+	v.SetText(a.keys.help(scope))
+
+	v.bindings = cbind.NewConfiguration()
+	a.keys.bind(v.bindings, ActionBack, v.onDone)
+	a.keys.bind(v.bindings, ActionHelp, v.onDone)
+	v.SetInputCapture(v.bindings.Capture)
+
+	a.app.SetRoot(v, true)
+}
+
+func (v *HelpView) onDone(ev *tcell.EventKey) *tcell.EventKey {
+	v.app.app.SetRoot(v.prev, true)
+	return nil
+}
+
+// helpHandler returns a handler showing the help of a full screen view
+func (a *App) helpHandler(scope string, view cview.Primitive) func(ev *tcell.EventKey) *tcell.EventKey {
+	return func(ev *tcell.EventKey) *tcell.EventKey {
+		a.showHelp(scope, view)
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+package ui
+
+import "testing"
+
+func TestNewKeymap(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		wantErr   bool
+	}{
+		{name: "defaults"},
+		{name: "rebound", overrides: map[string][]string{ActionHelp: {"F1", "h"}}},
+		{name: "same key in other views", overrides: map[string][]string{ActionReplay: {"s"}}},
+		{name: "global conflict", overrides: map[string][]string{ActionHelp: {"Esc"}}, wantErr: true},
+		{name: "view conflict", overrides: map[string][]string{ActionCopy: {"o"}}, wantErr: true},
+		{name: "global conflict in a tab", overrides: map[string][]string{ActionOpenBrowser: {"?"}}, wantErr: true},
+		{name: "unknown action", overrides: map[string][]string{"nope": {"x"}}, wantErr: true},
+		{name: "no keys", overrides: map[string][]string{ActionHelp: {}}, wantErr: true},
+		{name: "invalid key", overrides: map[string][]string{ActionHelp: {"Bogus"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeymap(tt.overrides)
+			if tt.wantErr && err == nil {
+				t.Fatal("NewKeymap succeeded, want an error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("NewKeymap: %v", err)
+			}
+		})
+	}
+}
@@ -35,23 +35,51 @@ func (tl *TransacionLogs) Update() {
 	}
 	tl.SetRoot(cview.NewTreeNode("Loading logs..."))
 	txn := tl.app.State.txn
+	tl.txn = txn
+	if txn != nil {
+		go tl.load(tl.app.ctx, txn)
+	}
+}
 
-	rec, err := tl.app.client.TransactionReceipt(context.TODO(), txn.Hash())
+// load fetches the logs with the contract abi, the names of the emitting
+// addresses and the event signatures off the ui goroutine, rendering them
+// unless another transaction was shown meanwhile
+func (tl *TransacionLogs) load(ctx context.Context, txn *types.Transaction) {
+	rec, err := tl.app.client.TransactionReceipt(ctx, tl.app.client.TxHash(txn))
 	if err != nil {
-		tl.app.log.Error("failed to get txn receipt")
+		tl.app.log.Error("failed to get txn receipt: ", err)
 		return
 	}
-	tl.txn = txn
-	tl.logs = rec.Logs
-	abi, err := util.GetContractABI(txn.To().String(), tl.app.config.EtherscanKey)
-	if err != nil {
-		tl.app.log.Errorf("failed to get contract abi: %s %s", txn.To().String(), err)
+	var contractABI *abi.ABI
+	if txn.To() != nil {
+		contractABI, err = util.GetContractABI(txn.To().String(), tl.app.chain.API)
+		if err != nil {
+			tl.app.log.Errorf("failed to get contract abi: %s %s", txn.To().String(), err)
+		}
 	}
-	tl.abi = abi
+
+	names := make(map[common.Address]addressName)
+	events := make(map[common.Hash]string)
+	for _, l := range rec.Logs {
+		if _, ok := names[l.Address]; !ok {
+			names[l.Address] = tl.app.lookupAddress(l.Address)
+		}
+		if len(l.Topics) == 0 {
+			continue
+		}
+		if _, ok := events[l.Topics[0]]; !ok {
+			events[l.Topics[0]] = tl.eventSig(contractABI, l.Topics[0])
+		}
+	}
+
 	tl.app.app.QueueUpdateDraw(func() {
-		tl.render()
+		if tl.txn != txn {
+			return
+		}
+		tl.logs = rec.Logs
+		tl.abi = contractABI
+		tl.render(names, events)
 	})
-
 }
 
 func (tl *TransacionLogs) decodeLogData(log *types.Log) *cview.TreeNode {
@@ -78,19 +106,16 @@ func (tl *TransacionLogs) decodeLogData(log *types.Log) *cview.TreeNode {
 
 }
 
-func (tl *TransacionLogs) buildTopic(topic common.Hash, idx int, checkSig bool) *cview.TreeNode {
-	if !checkSig {
-		trimmed := util.HexStripZeros(topic.Hex())
-		return cview.NewTreeNode(fmt.Sprintf("%d: %s", idx, trimmed))
-	}
-
+// eventSig returns the signature of an event topic from the abi, or else
+// the signature database, empty when it is unknown
+func (tl *TransacionLogs) eventSig(contractABI *abi.ABI, topic common.Hash) string {
 	// first get method from abi if we have it
-	if tl.abi != nil {
-		event, err := tl.abi.EventByID(topic)
+	if contractABI != nil {
+		event, err := contractABI.EventByID(topic)
 		if err != nil {
 			tl.app.log.Error("failed to get event from abi")
 		} else {
-			return cview.NewTreeNode(event.Sig)
+			return event.Sig
 		}
 	}
 
@@ -102,15 +127,24 @@ func (tl *TransacionLogs) buildTopic(topic common.Hash, idx int, checkSig bool)
 	sig, err := tl.db.GetSignature(prefix)
 	if err != nil {
 		tl.app.log.Error("failed to get topic signature: ", err)
-		return cview.NewTreeNode(fmt.Sprintf("%d: %s", idx, topic.Hex()))
-
+		return ""
 	}
 	tl.app.log.Debug("got signature for %s", prefix)
-	return cview.NewTreeNode(sig.TextSignature)
+	return sig.TextSignature
+}
 
+func (tl *TransacionLogs) buildTopic(topic common.Hash, idx int, events map[common.Hash]string) *cview.TreeNode {
+	if idx > 0 {
+		trimmed := util.HexStripZeros(topic.Hex())
+		return cview.NewTreeNode(fmt.Sprintf("%d: %s", idx, trimmed))
+	}
+	if sig := events[topic]; sig != "" {
+		return cview.NewTreeNode(sig)
+	}
+	return cview.NewTreeNode(fmt.Sprintf("%d: %s", idx, topic.Hex()))
 }
 
-func (tl *TransacionLogs) render() {
+func (tl *TransacionLogs) render(names map[common.Address]addressName, events map[common.Hash]string) {
 	tl.SetTitle("Logs")
 	tl.SetBorder(true)
 	tl.SetRoot(cview.NewTreeNode("."))
@@ -120,18 +154,11 @@ func (tl *TransacionLogs) render() {
 	}
 
 	for _, l := range tl.logs {
-		addr := cview.NewTreeNode(fmt.Sprintf("Address: %s", util.FormatAddress(tl.app.client, l.Address)))
+		addr := cview.NewTreeNode(fmt.Sprintf("Address: %s", tl.app.renderAddressName(names[l.Address])))
 
 		topics := cview.NewTreeNode("Topics")
 		for i, t := range l.Topics {
-			var n *cview.TreeNode
-			if i == 0 {
-				n = tl.buildTopic(t, i, true)
-			} else {
-				n = tl.buildTopic(t, i, false)
-			}
-			topics.AddChild(n)
-
+			topics.AddChild(tl.buildTopic(t, i, events))
 		}
 		addr.AddChild(topics)
 
@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/aquilax/truncate"
+	"github.com/atotto/clipboard"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/treethought/ethscan/util"
+)
+
+const (
+	maxMempoolRows = 200
+	// mempoolFetchers bounds concurrent transaction lookups, hashes arriving
+	// while all are busy are dropped rather than queued
+	mempoolFetchers = 8
+)
+
+// MempoolTable streams pending transactions seen by the node.
+type MempoolTable struct {
+	*cview.Table
+	app      *App
+	bindings *cbind.Configuration
+	started  bool
+	sem      chan struct{}
+}
+
+func NewMempoolTable(app *App) *MempoolTable {
+	t := &MempoolTable{
+		Table: cview.NewTable(),
+		app:   app,
+		sem:   make(chan struct{}, mempoolFetchers),
+	}
+	t.SetBorder(true)
+	t.SetTitle("Mempool (hit enter to open once mined)")
+	t.SetFixed(1, 0)
+	t.SetSelectable(true, false)
+	t.SetSelectedStyle(app.theme.Selected.tcell())
+	t.SetSelectedFunc(t.handleSelect)
+	t.setHeader()
+	t.initBindings()
+	return t
+}
+
+func (t *MempoolTable) initBindings() {
+	t.bindings = cbind.NewConfiguration()
+	t.SetInputCapture(t.bindings.Capture)
+	t.app.keys.bind(t.bindings, ActionCopy, t.handleCopy)
+}
+
+func (t *MempoolTable) getCurrentRef() *types.Transaction {
+	row, _ := t.GetSelection()
+	txn, ok := t.GetCell(row, 0).GetReference().(*types.Transaction)
+	if !ok {
+		return nil
+	}
+	return txn
+}
+
+func (t *MempoolTable) handleCopy(ev *tcell.EventKey) *tcell.EventKey {
+	txn := t.getCurrentRef()
+	if txn == nil {
+		return nil
+	}
+	if err := clipboard.WriteAll(t.app.client.TxHash(txn).String()); err != nil {
+		t.app.log.Error("failed to copy hash: ", err)
+	}
+	return nil
+}
+
+func (t *MempoolTable) Position() (int, int) {
+	row, _ := t.GetSelection()
+	offset, _ := t.GetOffset()
+	return row, offset
+}
+
+func (t *MempoolTable) SetPosition(row, offset int) {
+	t.Select(row, 0)
+	t.SetOffset(offset, 0)
+}
+
+func (t *MempoolTable) setHeader() {
+	value := fmt.Sprintf("Value (%s)", t.app.chain.Symbol())
+	for col, h := range []string{"Txn Hash", "From", "To", value, "Gas Price (Gwei)", "Nonce"} {
+		cell := cview.NewTableCell(h)
+		cell.SetSelectable(false)
+		t.SetCell(0, col, cell)
+	}
+}
+
+// Update starts the pending transaction subscription the first time the
+// view is shown
+func (t *MempoolTable) Update() {
+	if t.started {
+		return
+	}
+	t.started = true
+	go t.watch(t.app.ctx)
+}
+
+func (t *MempoolTable) watch(ctx context.Context) {
+	hashes := make(chan common.Hash)
+	sub, err := t.app.rpc.EthSubscribe(ctx, hashes, "newPendingTransactions")
+	if err != nil {
+		t.app.log.Error("failed to subscribe to pending transactions: ", err)
+		t.app.app.QueueUpdateDraw(func() {
+			t.SetTitle(fmt.Sprintf("Mempool %s %s", t.app.theme.Failure.Render("unavailable:"), cview.Escape(err.Error())))
+			t.started = false
+		})
+		return
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case err := <-sub.Err():
+			t.app.log.Error("pending transaction subscription closed: ", err)
+			t.app.app.QueueUpdateDraw(func() {
+				t.started = false
+			})
+			return
+		case h := <-hashes:
+			select {
+			case t.sem <- struct{}{}:
+				go t.fetch(ctx, h)
+			default:
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (t *MempoolTable) fetch(ctx context.Context, h common.Hash) {
+	defer func() { <-t.sem }()
+
+	txn, pending, err := t.app.client.TransactionByHash(ctx, h)
+	if err != nil || !pending {
+		return
+	}
+	from := ""
+	if sender, err := t.app.senders.Sender(ctx, txn); err == nil {
+		from = sender.Hex()
+	}
+	t.app.app.QueueUpdateDraw(func() {
+		t.addTxn(txn, from)
+	})
+}
+
+func (t *MempoolTable) addTxn(txn *types.Transaction, from string) {
+	to := t.app.theme.Contract.Render("ContractDeployment")
+	if txn.To() != nil {
+		to = txn.To().Hex()
+	}
+
+	// newest on top
+	t.InsertRow(1)
+	hash := cview.NewTableCell(truncate.Truncate(t.app.client.TxHash(txn).Hex(), truncSize, "...", truncate.PositionMiddle))
+	hash.SetReference(txn)
+	t.SetCell(1, 0, hash)
+	t.SetCell(1, 1, cview.NewTableCell(from))
+	t.SetCell(1, 2, cview.NewTableCell(to))
+	t.SetCell(1, 3, cview.NewTableCell(util.WeiToEther(txn.Value()).String()))
+	t.SetCell(1, 4, cview.NewTableCell(util.WeiToGwei(txn.GasFeeCap()).Text('f', 2)))
+	t.SetCell(1, 5, cview.NewTableCell(fmt.Sprint(txn.Nonce())))
+
+	for t.GetRowCount() > maxMempoolRows+1 {
+		t.RemoveRow(t.GetRowCount() - 1)
+	}
+}
+
+func (t *MempoolTable) handleSelect(row, _ int) {
+	if row == 0 {
+		return
+	}
+	txn := t.getCurrentRef()
+	if txn == nil {
+		return
+	}
+	t.app.cmdBar.Run(fmt.Sprintf("tx %s", t.app.client.TxHash(txn).Hex()))
+}
@@ -0,0 +1,218 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/aquilax/truncate"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	maxHistory     = 100
+	maxBreadcrumbs = 5
+)
+
+// Location is an entry of the navigation history, the view and the
+// objects it was showing.
+type Location struct {
+	View  string
+	Block *types.Block
+	Txn   *types.Transaction
+	// TxnHash is the hash of Txn, that of the rollup transaction for
+	// stand-ins
+	TxnHash  common.Hash
+	Contract *common.Address
+	Address  *common.Address
+
+	// Row and Offset are the selected row and scroll position
+	Row    int
+	Offset int
+}
+
+// Positioner is implemented by views which can restore their selection
+// and scroll position when navigating back to them.
+type Positioner interface {
+	Position() (row, offset int)
+	SetPosition(row, offset int)
+}
+
+// Label is the breadcrumb text of the location
+func (l Location) Label() string {
+	short := func(s string) string {
+		return truncate.Truncate(s, truncSize, "...", truncate.PositionMiddle)
+	}
+	switch l.View {
+	case "blockFeed":
+		return "blocks"
+	case "blockData":
+		if l.Block != nil {
+			return fmt.Sprintf("block #%s", l.Block.Number())
+		}
+	case "txnData":
+		if l.Txn != nil {
+			return fmt.Sprintf("txn %s", short(l.TxnHash.Hex()))
+		}
+	case "contract":
+		if l.Contract != nil {
+			return fmt.Sprintf("contract %s", short(l.Contract.Hex()))
+		}
+	case "address":
+		if l.Address != nil {
+			return fmt.Sprintf("addr %s", short(l.Address.Hex()))
+		}
+	}
+	return l.View
+}
+
+func (l Location) same(o Location) bool {
+	return l.View == o.View && l.Label() == o.Label()
+}
+
+// location captures the current state as shown by the view
+func (s *State) location(view string) Location {
+	s.Lock()
+	defer s.Unlock()
+	return Location{
+		View:     view,
+		Block:    s.block,
+		Txn:      s.txn,
+		Contract: s.contractAddress,
+		Address:  s.address,
+	}
+}
+
+// Push makes loc the current location, discarding the forward history
+func (s *State) Push(loc Location) {
+	s.Lock()
+	defer s.Unlock()
+	if s.current.View == "" {
+		s.current = loc
+		return
+	}
+	if s.current.same(loc) {
+		return
+	}
+	s.back = append(s.back, s.current)
+	if len(s.back) > maxHistory {
+		s.back = s.back[1:]
+	}
+	s.forward = nil
+	s.current = loc
+}
+
+// Back moves to the previous location, returning false if there is none
+func (s *State) Back() (Location, bool) {
+	s.Lock()
+	defer s.Unlock()
+	if len(s.back) == 0 {
+		return s.current, false
+	}
+	s.forward = append(s.forward, s.current)
+	s.current = s.back[len(s.back)-1]
+	s.back = s.back[:len(s.back)-1]
+	return s.current, true
+}
+
+// Forward moves to the next location, returning false if there is none
+func (s *State) Forward() (Location, bool) {
+	s.Lock()
+	defer s.Unlock()
+	if len(s.forward) == 0 {
+		return s.current, false
+	}
+	s.back = append(s.back, s.current)
+	s.current = s.forward[len(s.forward)-1]
+	s.forward = s.forward[:len(s.forward)-1]
+	return s.current, true
+}
+
+func (s *State) setPosition(row, offset int) {
+	s.Lock()
+	defer s.Unlock()
+	s.current.Row = row
+	s.current.Offset = offset
+}
+
+func (a *App) handleBack(ev *tcell.EventKey) *tcell.EventKey {
+	if !a.root.HasFocus() {
+		return ev
+	}
+	a.savePosition()
+	if loc, ok := a.State.Back(); ok {
+		a.restore(loc)
+	}
+	return nil
+}
+
+func (a *App) handleForward(ev *tcell.EventKey) *tcell.EventKey {
+	if !a.root.HasFocus() {
+		return ev
+	}
+	a.savePosition()
+	if loc, ok := a.State.Forward(); ok {
+		a.restore(loc)
+	}
+	return nil
+}
+
+// savePosition records the selection of the current view before leaving it
+func (a *App) savePosition() {
+	view, ok := a.views[a.State.current.View].(Positioner)
+	if !ok {
+		return
+	}
+	a.State.setPosition(view.Position())
+}
+
+// restore shows a location from the history with the objects it had
+func (a *App) restore(loc Location) {
+	a.log.Info("restoring location: ", loc.Label())
+	a.State.SetBlock(loc.Block)
+	a.State.SetTxn(loc.Txn)
+	a.State.SetContract(loc.Contract)
+	a.State.SetAddress(loc.Address)
+
+	if view, ok := a.views[loc.View]; ok {
+		view.Update()
+	}
+	if loc.View == "txnData" {
+		a.views["txnLogs"].Update()
+	}
+	a.root.SetCurrentTab(loc.View)
+	a.renderBreadcrumbs()
+
+	if view, ok := a.views[loc.View].(Positioner); ok {
+		// views render asynchronously, so restore after their update
+		a.app.QueueUpdateDraw(func() {
+			view.SetPosition(loc.Row, loc.Offset)
+		})
+	}
+}
+
+func (a *App) renderBreadcrumbs() {
+	a.State.Lock()
+	back := a.State.back
+	current := a.State.current
+	forward := len(a.State.forward)
+	a.State.Unlock()
+
+	var crumbs []string
+	if len(back) > maxBreadcrumbs {
+		crumbs = append(crumbs, "...")
+		back = back[len(back)-maxBreadcrumbs:]
+	}
+	for _, l := range back {
+		crumbs = append(crumbs, cview.Escape(l.Label()))
+	}
+	crumbs = append(crumbs, fmt.Sprintf("[::b]%s[::-]", cview.Escape(current.Label())))
+
+	text := strings.Join(crumbs, " > ")
+	if forward > 0 {
+		text = fmt.Sprintf("%s  %s", text, a.theme.Muted.Render(fmt.Sprintf("(%d forward, hit `%s`)", forward, cview.Escape(a.keys.Keys(ActionForward)))))
+	}
+	a.crumbs.SetText(text)
+}
@@ -0,0 +1,309 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/treethought/ethscan/util"
+)
+
+// outcome is the comparable result of an execution
+type outcome struct {
+	status     string
+	gasUsed    string
+	returnData string
+	logs       []string
+}
+
+// ReplayView re-runs a mined transaction with modified parameters and
+// compares the result against the original execution.
+type ReplayView struct {
+	*cview.Flex
+	app       *App
+	txn       *types.Transaction
+	block     *types.Block
+	form      *cview.Form
+	origView  *cview.TextView
+	simView   *cview.TextView
+	bindings  *cbind.Configuration
+	fromField *cview.InputField
+	dataField *cview.InputField
+	valField  *cview.InputField
+	gasField  *cview.InputField
+	blkField  *cview.InputField
+	fund      *cview.CheckBox
+
+	// abi and original are set on the ui goroutine once loaded
+	abi      *abi.ABI
+	original *outcome
+}
+
+func NewReplayView(app *App, block *types.Block, txn *types.Transaction) *ReplayView {
+	v := &ReplayView{
+		Flex:     cview.NewFlex(),
+		app:      app,
+		txn:      txn,
+		block:    block,
+		form:     cview.NewForm(),
+		origView: cview.NewTextView(),
+		simView:  cview.NewTextView(),
+	}
+	v.SetDirection(cview.FlexColumn)
+	v.SetTitle(fmt.Sprintf("Re-simulate %s", app.client.TxHash(txn).Hex()))
+	v.SetBorder(true)
+
+	parent := new(big.Int).Sub(block.Number(), big.NewInt(1))
+
+	v.form.SetTitle("Parameters")
+	v.form.SetBorder(true)
+	v.fromField = v.addInput("From", "")
+	v.fromField.SetPlaceholder("loading sender...")
+	v.dataField = v.addInput("Calldata", hexutil.Encode(txn.Data()))
+	v.valField = v.addInput("Value (wei)", txn.Value().String())
+	v.gasField = v.addInput("Gas", fmt.Sprint(txn.Gas()))
+	v.blkField = v.addInput("Block", parent.String())
+	v.fund = cview.NewCheckBox()
+	v.fund.SetLabel("Fund sender")
+	v.form.AddFormItem(v.fund)
+	v.form.AddButton("Simulate", v.simulate)
+	v.form.SetCancelFunc(v.close)
+
+	for _, tv := range []*cview.TextView{v.origView, v.simView} {
+		tv.SetBorder(true)
+		tv.SetDynamicColors(true)
+		tv.SetScrollable(true)
+		tv.SetWordWrap(true)
+	}
+	v.origView.SetTitle("Original")
+	v.origView.SetText("Loading...")
+	v.simView.SetTitle("Simulated")
+
+	v.AddItem(v.form, 0, 1, true)
+	v.AddItem(v.origView, 0, 1, false)
+	v.AddItem(v.simView, 0, 1, false)
+
+	v.initBindings()
+	go v.loadOriginal()
+	return v
+}
+
+func (v *ReplayView) addInput(label, value string) *cview.InputField {
+	field := cview.NewInputField()
+	field.SetLabel(label)
+	field.SetText(value)
+	v.form.AddFormItem(field)
+	return field
+}
+
+func (v *ReplayView) initBindings() {
+	v.bindings = cbind.NewConfiguration()
+	v.bindings.SetKey(tcell.ModNone, tcell.KeyEsc, v.onDone)
+	v.SetInputCapture(v.bindings.Capture)
+}
+
+func (v *ReplayView) onDone(ev *tcell.EventKey) *tcell.EventKey {
+	v.close()
+	return nil
+}
+
+func (v *ReplayView) close() {
+	v.app.app.SetRoot(v.app.layout, true)
+}
+
+// loadOriginal fetches the sender, contract abi, receipt and trace when
+// available of the original transaction
+func (v *ReplayView) loadOriginal() {
+	ctx := context.TODO()
+	from, senderErr := v.app.senders.Sender(ctx, v.txn)
+	if senderErr != nil {
+		v.app.log.Error("failed to get txn sender: ", senderErr)
+	}
+	v.app.app.QueueUpdateDraw(func() {
+		v.fromField.SetPlaceholder("")
+		// keep an address typed meanwhile
+		if senderErr == nil && v.fromField.GetText() == "" {
+			v.fromField.SetText(from.Hex())
+		}
+	})
+
+	var contractABI *abi.ABI
+	if v.txn.To() != nil {
+		var err error
+		contractABI, err = util.GetContractABI(v.txn.To().String(), v.app.chain.API)
+		if err != nil {
+			v.app.log.Error("failed to get abi: ", err)
+		}
+	}
+	v.app.app.QueueUpdateDraw(func() {
+		v.abi = contractABI
+	})
+
+	rec, err := v.app.client.TransactionReceipt(ctx, v.app.client.TxHash(v.txn))
+	if err != nil {
+		v.app.log.Error("failed to get txn receipt: ", err)
+		v.app.app.QueueUpdateDraw(func() {
+			v.origView.SetText(fmt.Sprintf("%s %s", v.app.theme.Failure.Render("failed to get receipt:"), cview.Escape(err.Error())))
+		})
+		return
+	}
+
+	o := &outcome{
+		status:     "Success",
+		gasUsed:    fmt.Sprint(rec.GasUsed),
+		returnData: "unavailable",
+	}
+	if rec.Status != types.ReceiptStatusSuccessful {
+		o.status = "Reverted"
+	}
+	for _, l := range rec.Logs {
+		o.logs = append(o.logs, formatLog(contractABI, util.SimulatedLog{Address: l.Address, Topics: l.Topics, Data: l.Data}))
+	}
+
+	trace, err := util.TraceTransaction(ctx, v.app.rpc, v.app.client.TxHash(v.txn))
+	if err != nil {
+		v.app.log.Error("failed to trace txn: ", err)
+	} else {
+		o.returnData = v.formatReturn(contractABI, trace)
+	}
+
+	v.app.app.QueueUpdateDraw(func() {
+		v.original = o
+		v.origView.SetText(renderOutcome(v.app.theme, o, nil))
+	})
+}
+
+func (v *ReplayView) formatReturn(contractABI *abi.ABI, res *util.SimulationResult) string {
+	if res.Reverted {
+		reason := util.DecodeRevert(contractABI, res.RevertData)
+		if reason == "" {
+			return res.Error
+		}
+		return reason
+	}
+	if len(res.ReturnData) == 0 {
+		return "0x"
+	}
+	if contractABI != nil && len(v.txn.Data()) >= 4 {
+		if m, err := contractABI.MethodById(v.txn.Data()[:4]); err == nil {
+			if values, err := m.Outputs.Unpack(res.ReturnData); err == nil {
+				return util.FormatValue(values)
+			}
+		}
+	}
+	return hexutil.Encode(res.ReturnData)
+}
+
+func (v *ReplayView) callMsg() (util.CallMsg, error) {
+	msg := util.CallMsg{To: v.txn.To()}
+
+	from := strings.TrimSpace(v.fromField.GetText())
+	if !common.IsHexAddress(from) {
+		return msg, fmt.Errorf("invalid from address: %q", from)
+	}
+	msg.From = common.HexToAddress(from)
+
+	data, err := hexutil.Decode(strings.TrimSpace(v.dataField.GetText()))
+	if err != nil {
+		return msg, fmt.Errorf("invalid calldata: %w", err)
+	}
+	msg.Data = data
+
+	value, err := util.ParseArg(abi.Type{T: abi.UintTy, Size: 256}, v.valField.GetText())
+	if err != nil {
+		return msg, fmt.Errorf("invalid value: %w", err)
+	}
+	msg.Value = value.(*big.Int)
+
+	msg.Gas, err = strconv.ParseUint(strings.TrimSpace(v.gasField.GetText()), 10, 64)
+	if err != nil {
+		return msg, fmt.Errorf("invalid gas: %s", v.gasField.GetText())
+	}
+	return msg, nil
+}
+
+func (v *ReplayView) simulate() {
+	msg, err := v.callMsg()
+	if err != nil {
+		v.simView.SetText(fmt.Sprintf("%s %s", v.app.theme.Failure.Render("invalid input:"), cview.Escape(err.Error())))
+		return
+	}
+	block, err := util.ParseBlockTag(v.blkField.GetText())
+	if err != nil {
+		v.simView.SetText(v.app.theme.Failure.Render(cview.Escape(err.Error())))
+		return
+	}
+
+	fund := v.fund.IsChecked()
+	contractABI, original := v.abi, v.original
+	v.simView.SetText("Simulating...")
+	go func() {
+		res, err := util.Simulate(context.TODO(), v.app.rpc, msg, block, fund)
+		if err != nil {
+			v.app.log.Error("simulation failed: ", err)
+			v.app.app.QueueUpdateDraw(func() {
+				v.simView.SetText(fmt.Sprintf("%s %s", v.app.theme.Failure.Render("simulation failed:"), cview.Escape(err.Error())))
+			})
+			return
+		}
+		if res.TraceErr != nil {
+			v.app.log.Error("debug_traceCall failed, fell back to eth_call: ", res.TraceErr)
+		}
+
+		o := &outcome{
+			status:     "Success",
+			gasUsed:    "unavailable",
+			returnData: v.formatReturn(contractABI, res),
+		}
+		if res.Reverted {
+			o.status = "Reverted"
+		}
+		if res.Traced {
+			o.gasUsed = fmt.Sprint(res.GasUsed)
+		}
+		for _, l := range res.Logs {
+			o.logs = append(o.logs, formatLog(contractABI, l))
+		}
+
+		v.app.app.QueueUpdateDraw(func() {
+			v.simView.SetText(renderOutcome(v.app.theme, o, original))
+		})
+	}()
+}
+
+// renderOutcome formats an outcome, highlighting fields that differ from
+// the outcome it is compared against
+func renderOutcome(theme *Theme, o, compare *outcome) string {
+	if compare == nil {
+		compare = o
+	}
+	field := func(label, val, other string) string {
+		if val != other {
+			return fmt.Sprintf("%s: %s\n", label, theme.Highlight.Render(cview.Escape(val)))
+		}
+		return fmt.Sprintf("%s: %s\n", label, cview.Escape(val))
+	}
+
+	var b strings.Builder
+	b.WriteString(field("Status", o.status, compare.status))
+	b.WriteString(field("Gas Used", o.gasUsed, compare.gasUsed))
+	b.WriteString(field("Return Data", o.returnData, compare.returnData))
+	b.WriteString(field("Logs", fmt.Sprint(len(o.logs)), fmt.Sprint(len(compare.logs))))
+	for i, l := range o.logs {
+		other := ""
+		if i < len(compare.logs) {
+			other = compare.logs[i]
+		}
+		b.WriteString(field(cview.Escape(fmt.Sprintf("  [%d]", i)), l, other))
+	}
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/treethought/ethscan/util"
+)
+
+// SearchResults lets the user pick between the matches of an ambiguous
+// search.
+type SearchResults struct {
+	*cview.List
+	app      *App
+	bindings *cbind.Configuration
+}
+
+func NewSearchResults(app *App, input string, refs []util.Ref) *SearchResults {
+	r := &SearchResults{
+		List: cview.NewList(),
+		app:  app,
+	}
+	r.SetTitle(fmt.Sprintf("%d matches for %s", len(refs), input))
+	r.SetBorder(true)
+
+	for _, ref := range refs {
+		item := cview.NewListItem(cview.Escape(ref.String()))
+		item.SetSecondaryText(ref.Kind.String())
+		item.SetReference(ref)
+		r.AddItem(item)
+	}
+	r.SetSelectedFunc(r.handleSelect)
+
+	r.initBindings()
+	return r
+}
+
+func (r *SearchResults) initBindings() {
+	r.bindings = cbind.NewConfiguration()
+	r.app.keys.bind(r.bindings, ActionBack, r.onDone)
+	r.SetInputCapture(r.bindings.Capture)
+}
+
+func (r *SearchResults) onDone(ev *tcell.EventKey) *tcell.EventKey {
+	r.app.app.SetRoot(r.app.layout, true)
+	return nil
+}
+
+func (r *SearchResults) handleSelect(_ int, item *cview.ListItem) {
+	ref, ok := item.GetReference().(util.Ref)
+	if !ok {
+		return
+	}
+	r.app.app.SetRoot(r.app.layout, true)
+	r.app.cmdBar.SetPlaceholder(fmt.Sprintf("opening %s...", ref.Kind))
+	go func() {
+		r.app.cmdBar.finish(r.app.cmdBar.open(context.TODO(), ref))
+	}()
+}
@@ -0,0 +1,361 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/treethought/ethscan/util"
+)
+
+type promptMode int
+
+const (
+	promptSearch promptMode = iota
+	promptDefinition
+)
+
+// SourceCodeView browses the files of a verified contract with a file
+// tree, highlighted viewer, search and jump to definition.
+type SourceCodeView struct {
+	*cview.Flex
+	app      *App
+	bindings *cbind.Configuration
+	contract *util.ContractData
+
+	files []util.SourceFile
+	defs  []util.Definition
+
+	tree   *cview.TreeView
+	viewer *cview.TextView
+	prompt *cview.InputField
+	mode   promptMode
+
+	current int
+	query   string
+	matches []int
+	match   int
+}
+
+func NewSourceCodeView(app *App, contract *util.ContractData) *SourceCodeView {
+	c := &SourceCodeView{
+		Flex:     cview.NewFlex(),
+		app:      app,
+		contract: contract,
+		tree:     cview.NewTreeView(),
+		viewer:   cview.NewTextView(),
+		prompt:   cview.NewInputField(),
+		current:  -1,
+	}
+
+	if c.contract != nil {
+		files, err := c.contract.SourceFiles()
+		if err != nil {
+			app.log.Error("failed to parse source files: ", err)
+			files = []util.SourceFile{{Path: c.contract.ContractName + ".sol", Content: c.contract.SourceCode}}
+		}
+		c.files = files
+		c.defs = util.FindDefinitions(files)
+	}
+
+	c.tree.SetTitle("Files")
+	c.tree.SetBorder(true)
+	c.tree.SetSelectedFunc(func(n *cview.TreeNode) {
+		if i, ok := n.GetReference().(int); ok {
+			c.showFile(i, -1)
+			c.app.app.SetFocus(c.viewer)
+		}
+	})
+	c.buildTree()
+
+	c.viewer.SetBorder(true)
+	c.viewer.SetDynamicColors(true)
+	c.viewer.SetRegions(true)
+	c.viewer.SetScrollable(true)
+	c.viewer.SetWrap(false)
+
+	c.prompt.SetDoneFunc(c.onPromptDone)
+	c.prompt.SetAutocompleteFunc(c.autocomplete)
+
+	right := cview.NewFlex()
+	right.SetDirection(cview.FlexRow)
+	right.AddItem(c.viewer, 0, 1, false)
+	right.AddItem(c.prompt, 1, 0, false)
+
+	c.SetDirection(cview.FlexColumn)
+	c.AddItem(c.tree, 0, 1, len(c.files) > 1)
+	c.AddItem(right, 0, 4, len(c.files) <= 1)
+
+	c.initBindings()
+	if len(c.files) > 0 {
+		c.showFile(0, -1)
+	}
+	return c
+}
+
+func (s *SourceCodeView) initBindings() {
+	s.bindings = cbind.NewConfiguration()
+	s.app.keys.bind(s.bindings, ActionBack, s.onDone)
+	s.app.keys.bind(s.bindings, ActionHelp, s.app.helpHandler("source", s))
+	s.app.keys.bind(s.bindings, ActionToggleFocus, s.toggleFocus)
+	s.app.keys.bind(s.bindings, ActionOpenEditor, s.openEditor)
+	s.app.keys.bind(s.bindings, ActionFind, s.startSearch)
+	s.app.keys.bind(s.bindings, ActionFindNext, s.nextMatch)
+	s.app.keys.bind(s.bindings, ActionFindPrev, s.prevMatch)
+	s.app.keys.bind(s.bindings, ActionJumpToDefinition, s.startDefinition)
+	s.tree.SetInputCapture(s.bindings.Capture)
+	s.viewer.SetInputCapture(s.bindings.Capture)
+}
+
+func (s *SourceCodeView) onDone(ev *tcell.EventKey) *tcell.EventKey {
+	s.app.app.SetRoot(s.app.layout, true)
+	return nil
+}
+
+func (s *SourceCodeView) toggleFocus(ev *tcell.EventKey) *tcell.EventKey {
+	if s.tree.HasFocus() {
+		s.app.app.SetFocus(s.viewer)
+	} else {
+		s.app.app.SetFocus(s.tree)
+	}
+	return nil
+}
+
+// buildTree creates the directory tree of the source files
+func (s *SourceCodeView) buildTree() {
+	name := "source"
+	if s.contract != nil {
+		name = s.contract.ContractName
+	}
+	root := cview.NewTreeNode(name)
+	dirs := map[string]*cview.TreeNode{"": root}
+
+	for i, f := range s.files {
+		parts := strings.Split(f.Path, "/")
+		parent := root
+		for j := range parts[:len(parts)-1] {
+			dir := strings.Join(parts[:j+1], "/")
+			node, ok := dirs[dir]
+			if !ok {
+				node = cview.NewTreeNode(parts[j] + "/")
+				parent.AddChild(node)
+				dirs[dir] = node
+			}
+			parent = node
+		}
+		leaf := cview.NewTreeNode(parts[len(parts)-1])
+		leaf.SetReference(i)
+		parent.AddChild(leaf)
+	}
+	s.tree.SetRoot(root)
+	s.tree.SetCurrentNode(root)
+}
+
+// showFile renders the file at index i, highlighting line when it is not
+// negative
+func (s *SourceCodeView) showFile(i, line int) {
+	if i < 0 || i >= len(s.files) {
+		return
+	}
+	if i != s.current {
+		s.current = i
+		s.query = ""
+		s.matches = nil
+		s.viewer.SetText(renderSource(s.app.theme, s.files[i].Content))
+	}
+	s.viewer.SetTitle(s.files[i].Path)
+	if line >= 0 {
+		s.viewer.Highlight(lineRegion(line))
+		s.viewer.ScrollToHighlight()
+	} else {
+		s.viewer.Highlight()
+		s.viewer.ScrollToBeginning()
+	}
+}
+
+// ShowLocation opens the file with the given path and highlights a line
+func (s *SourceCodeView) ShowLocation(path string, line int) bool {
+	for i, f := range s.files {
+		if f.Path == path || strings.HasSuffix(f.Path, "/"+path) {
+			s.showFile(i, line)
+			return true
+		}
+	}
+	return false
+}
+
+func lineRegion(line int) string {
+	return fmt.Sprintf("L%d", line)
+}
+
+func renderSource(theme *Theme, content string) string {
+	lines := strings.Split(content, "\n")
+	width := len(fmt.Sprint(len(lines)))
+	h := &highlighter{theme: theme}
+
+	var b strings.Builder
+	for i, l := range lines {
+		num := theme.Muted.Render(fmt.Sprintf("%*d", width, i+1))
+		fmt.Fprintf(&b, "[\"%s\"]%s %s[\"\"]\n", lineRegion(i), num, h.line(l))
+	}
+	return b.String()
+}
+
+func (s *SourceCodeView) startSearch(ev *tcell.EventKey) *tcell.EventKey {
+	s.mode = promptSearch
+	s.prompt.SetLabel("/")
+	s.prompt.SetText(s.query)
+	s.app.app.SetFocus(s.prompt)
+	return nil
+}
+
+func (s *SourceCodeView) startDefinition(ev *tcell.EventKey) *tcell.EventKey {
+	s.mode = promptDefinition
+	s.prompt.SetLabel("definition: ")
+	s.prompt.SetText("")
+	s.app.app.SetFocus(s.prompt)
+	return nil
+}
+
+func (s *SourceCodeView) autocomplete(text string) []*cview.ListItem {
+	if s.mode != promptDefinition || text == "" {
+		return nil
+	}
+	var items []*cview.ListItem
+	for _, d := range s.defs {
+		if strings.HasPrefix(strings.ToLower(d.Name), strings.ToLower(text)) {
+			items = append(items, cview.NewListItem(d.Name))
+		}
+	}
+	return items
+}
+
+func (s *SourceCodeView) onPromptDone(key tcell.Key) {
+	text := strings.TrimSpace(s.prompt.GetText())
+	s.prompt.SetText("")
+	s.prompt.SetLabel("")
+	s.app.app.SetFocus(s.viewer)
+	if key != tcell.KeyEnter || text == "" {
+		return
+	}
+
+	switch s.mode {
+	case promptSearch:
+		s.search(text)
+	case promptDefinition:
+		s.jumpToDefinition(text)
+	}
+}
+
+// search finds the lines of the current file containing query
+func (s *SourceCodeView) search(query string) {
+	if s.current < 0 {
+		return
+	}
+	s.query = query
+	s.matches = nil
+	q := strings.ToLower(query)
+	for i, l := range strings.Split(s.files[s.current].Content, "\n") {
+		if strings.Contains(strings.ToLower(l), q) {
+			s.matches = append(s.matches, i)
+		}
+	}
+	s.match = -1
+	s.nextMatch(nil)
+}
+
+func (s *SourceCodeView) showMatch() {
+	path := s.files[s.current].Path
+	if len(s.matches) == 0 {
+		s.viewer.SetTitle(fmt.Sprintf("%s (no matches for %q)", path, s.query))
+		return
+	}
+	s.viewer.Highlight(lineRegion(s.matches[s.match]))
+	s.viewer.ScrollToHighlight()
+	s.viewer.SetTitle(fmt.Sprintf("%s (match %d/%d for %q)", path, s.match+1, len(s.matches), s.query))
+}
+
+func (s *SourceCodeView) nextMatch(ev *tcell.EventKey) *tcell.EventKey {
+	if s.current < 0 || s.query == "" {
+		return nil
+	}
+	if len(s.matches) > 0 {
+		s.match = (s.match + 1) % len(s.matches)
+	}
+	s.showMatch()
+	return nil
+}
+
+func (s *SourceCodeView) prevMatch(ev *tcell.EventKey) *tcell.EventKey {
+	if s.current < 0 || s.query == "" {
+		return nil
+	}
+	if len(s.matches) > 0 {
+		s.match = (s.match - 1 + len(s.matches)) % len(s.matches)
+	}
+	s.showMatch()
+	return nil
+}
+
+// jumpToDefinition shows the declaration of name, preferring contracts
+// over functions when a name is declared more than once
+func (s *SourceCodeView) jumpToDefinition(name string) {
+	var found *util.Definition
+	for i, d := range s.defs {
+		if d.Name != name {
+			continue
+		}
+		if found == nil || (found.Kind != "contract" && d.Kind == "contract") {
+			found = &s.defs[i]
+		}
+	}
+	if found == nil {
+		s.viewer.SetTitle(fmt.Sprintf("%s (no definition for %q)", s.files[s.current].Path, name))
+		return
+	}
+	s.showFile(found.File, found.Line)
+}
+
+func (s *SourceCodeView) openEditor(ev *tcell.EventKey) *tcell.EventKey {
+	if s.contract == nil {
+		return nil
+	}
+	dir, err := util.ExportSources(s.files, s.contract.ContractName)
+	if err != nil {
+		s.app.log.Error("failed to export source files: ", err)
+		return nil
+	}
+
+	target := dir
+	if s.current >= 0 {
+		target = filepath.Join(dir, filepath.FromSlash(s.files[s.current].Path))
+		if _, err := os.Stat(target); err != nil {
+			target = dir
+		}
+	}
+
+	s.app.app.Suspend(func() {
+		editor := os.Getenv("EDITOR")
+		path, err := exec.LookPath(editor)
+		if err != nil {
+			s.app.log.Error("failed to lookup $EDITOR")
+		}
+
+		cmd := exec.Command(path, target)
+		cmd.Dir = dir
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		err = cmd.Run()
+		if err != nil {
+			s.app.log.Error("failed to open editor: ", err)
+			return
+		}
+	})
+	return nil
+}
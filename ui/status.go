@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	notificationTimeout = 5 * time.Second
+	maxLogLines         = 1000
+)
+
+// StatusBar shows the connection state and transient notifications.
+type StatusBar struct {
+	*cview.TextView
+	app *App
+
+	sync.Mutex
+	head    *types.Header
+	message string
+	isError bool
+	expires time.Time
+}
+
+func NewStatusBar(app *App) *StatusBar {
+	s := &StatusBar{
+		TextView: cview.NewTextView(),
+		app:      app,
+	}
+	s.SetDynamicColors(true)
+	s.SetWrap(false)
+	return s
+}
+
+// redactEndpoint strips the path and credentials from the rpc url, which
+// commonly contain api keys
+func redactEndpoint(rpcURL string) string {
+	u, err := url.Parse(rpcURL)
+	if err != nil || u.Host == "" {
+		return "?"
+	}
+	return fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+}
+
+// watch follows the head of the connection's chain
+func (s *StatusBar) watch(ctx context.Context) {
+	s.Lock()
+	s.head = nil
+	s.Unlock()
+
+	headers := s.app.broker.SubscribeHeaders()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case h := <-headers:
+			s.Lock()
+			s.head = h
+			s.Unlock()
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		s.app.app.QueueUpdateDraw(s.render)
+	}
+}
+
+// notify sets the transient message
+func (s *StatusBar) notify(msg string, isError bool) {
+	s.Lock()
+	s.message = msg
+	s.isError = isError
+	s.expires = time.Now().Add(notificationTimeout)
+	s.Unlock()
+	s.app.app.QueueUpdateDraw(s.render)
+}
+
+func (s *StatusBar) render() {
+	s.Lock()
+	defer s.Unlock()
+
+	head := "waiting for blocks"
+	if s.head != nil {
+		lag := time.Since(time.Unix(int64(s.head.Time), 0)).Truncate(time.Second)
+		head = fmt.Sprintf("head #%s (%s ago)", s.head.Number, lag)
+		if lag > time.Minute {
+			head = s.app.theme.Warning.Render(head)
+		}
+	}
+	text := fmt.Sprintf("%s | %s | %s", cview.Escape(s.app.chain.String()), cview.Escape(redactEndpoint(s.app.chain.RpcUrl)), head)
+
+	if s.message != "" && time.Now().Before(s.expires) {
+		msg := cview.Escape(s.message)
+		if s.isError {
+			msg = s.app.theme.Failure.Render(msg)
+		}
+		text = fmt.Sprintf("%s | %s", text, msg)
+	}
+	s.SetText(text)
+}
+
+// notify shows an info message in the status bar, it is safe to call from
+// any goroutine
+func (a *App) notify(msg string) {
+	a.log.Info(msg)
+	a.status.notify(msg, false)
+}
+
+// notifyError logs the error and shows it in the status bar instead of
+// exiting, it is safe to call from any goroutine
+func (a *App) notifyError(msg string, err error) {
+	if msg != "" {
+		err = fmt.Errorf("%s: %w", msg, err)
+	}
+	a.log.Error(err)
+	a.status.notify(err.Error(), true)
+}
+
+// logBuffer keeps the most recent log lines for the log viewer
+type logBuffer struct {
+	sync.Mutex
+	lines   []string
+	partial string
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	data := b.partial + string(p)
+	lines := strings.Split(data, "\n")
+	b.partial = lines[len(lines)-1]
+	b.lines = append(b.lines, lines[:len(lines)-1]...)
+	if len(b.lines) > maxLogLines {
+		b.lines = b.lines[len(b.lines)-maxLogLines:]
+	}
+	return len(p), nil
+}
+
+func (b *logBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return strings.Join(b.lines, "\n")
+}
+
+// LogView shows the recent log messages.
+type LogView struct {
+	*cview.TextView
+	app      *App
+	bindings *cbind.Configuration
+}
+
+func NewLogView(app *App) *LogView {
+	v := &LogView{
+		TextView: cview.NewTextView(),
+		app:      app,
+	}
+	v.SetTitle(fmt.Sprintf("Log (hit `%s` to refresh)", app.keys.Keys(ActionRefresh)))
+	v.SetBorder(true)
+	v.SetScrollable(true)
+	v.refresh()
+	v.initBindings()
+	return v
+}
+
+func (v *LogView) initBindings() {
+	v.bindings = cbind.NewConfiguration()
+	v.app.keys.bind(v.bindings, ActionBack, v.onDone)
+	v.app.keys.bind(v.bindings, ActionHelp, v.app.helpHandler("log", v))
+	v.app.keys.bind(v.bindings, ActionRefresh, func(ev *tcell.EventKey) *tcell.EventKey {
+		v.refresh()
+		return nil
+	})
+	v.SetInputCapture(v.bindings.Capture)
+}
+
+func (v *LogView) refresh() {
+	v.SetText(v.app.logs.String())
+	v.ScrollToEnd()
+}
+
+func (v *LogView) onDone(ev *tcell.EventKey) *tcell.EventKey {
+	v.app.app.SetRoot(v.app.layout, true)
+	return nil
+}
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gdamore/tcell/v2"
+	"github.com/treethought/ethscan/util"
+)
+
+// StorageView reads raw storage slots of a contract.
+type StorageView struct {
+	*cview.Flex
+	app      *App
+	address  common.Address
+	form     *cview.Form
+	output   *cview.TextView
+	bindings *cbind.Configuration
+
+	slot  *cview.InputField
+	keys  *cview.InputField
+	index *cview.InputField
+	block *cview.InputField
+}
+
+func NewStorageView(app *App, address common.Address) *StorageView {
+	v := &StorageView{
+		Flex:    cview.NewFlex(),
+		app:     app,
+		address: address,
+		form:    cview.NewForm(),
+		output:  cview.NewTextView(),
+	}
+	v.SetDirection(cview.FlexColumn)
+	v.SetTitle(fmt.Sprintf("Storage %s", address.Hex()))
+	v.SetBorder(true)
+
+	v.form.SetTitle("Slot")
+	v.form.SetBorder(true)
+	v.slot = v.addInput("Slot (number or implementation/admin/beacon/rollback)", "0")
+	v.keys = v.addInput("Mapping keys (comma separated)", "")
+	v.index = v.addInput("Array index", "")
+	v.block = v.addInput("Block", "latest")
+	v.form.AddButton("Read", v.read)
+	v.form.SetCancelFunc(v.close)
+
+	v.output.SetTitle("Values")
+	v.output.SetBorder(true)
+	v.output.SetDynamicColors(true)
+	v.output.SetScrollable(true)
+
+	v.AddItem(v.form, 0, 1, true)
+	v.AddItem(v.output, 0, 1, false)
+
+	v.initBindings()
+	go v.readNamedSlots()
+	return v
+}
+
+func (v *StorageView) addInput(label, value string) *cview.InputField {
+	field := cview.NewInputField()
+	field.SetLabel(label)
+	field.SetText(value)
+	v.form.AddFormItem(field)
+	return field
+}
+
+func (v *StorageView) initBindings() {
+	v.bindings = cbind.NewConfiguration()
+	v.bindings.SetKey(tcell.ModNone, tcell.KeyEsc, v.onDone)
+	v.SetInputCapture(v.bindings.Capture)
+}
+
+func (v *StorageView) onDone(ev *tcell.EventKey) *tcell.EventKey {
+	v.close()
+	return nil
+}
+
+func (v *StorageView) close() {
+	v.app.app.SetRoot(v.app.layout, true)
+}
+
+// computeSlot derives the final slot from the base slot, mapping keys and
+// array index inputs
+func (v *StorageView) computeSlot() (common.Hash, error) {
+	slot, err := util.ParseSlot(v.slot.GetText())
+	if err != nil {
+		return slot, err
+	}
+
+	if keys := strings.TrimSpace(v.keys.GetText()); keys != "" {
+		for _, k := range strings.Split(keys, ",") {
+			slot = util.MappingSlot(util.EncodeMappingKey(k), slot)
+		}
+	}
+
+	if index := strings.TrimSpace(v.index.GetText()); index != "" {
+		i, ok := new(big.Int).SetString(index, 0)
+		if !ok || i.Sign() < 0 {
+			return slot, fmt.Errorf("invalid array index: %s", index)
+		}
+		slot = util.ArraySlot(slot, i)
+	}
+	return slot, nil
+}
+
+func (v *StorageView) read() {
+	slot, err := v.computeSlot()
+	if err != nil {
+		v.output.SetText(v.app.theme.Failure.Render(cview.Escape(err.Error())))
+		return
+	}
+	block, err := util.ParseBlockTag(v.block.GetText())
+	if err != nil {
+		v.output.SetText(v.app.theme.Failure.Render(cview.Escape(err.Error())))
+		return
+	}
+
+	v.output.SetText("Reading...")
+	go func() {
+		val, err := util.GetStorageAt(context.TODO(), v.app.rpc, v.address, slot, block)
+		v.app.app.QueueUpdateDraw(func() {
+			if err != nil {
+				v.app.log.Error("failed to read storage: ", err)
+				v.output.SetText(fmt.Sprintf("%s %s", v.app.theme.Failure.Render("failed to read storage:"), cview.Escape(err.Error())))
+				return
+			}
+			v.output.SetText(fmt.Sprintf("Slot: %s\n%s", slot.Hex(), formatStorageValue(val)))
+		})
+	}()
+}
+
+// readNamedSlots shows the EIP-1967 slots of the contract when it is
+// first opened, which covers the common proxy case
+func (v *StorageView) readNamedSlots() {
+	var b strings.Builder
+	b.WriteString("EIP-1967 slots (latest)\n\n")
+	for _, s := range util.EIP1967Slots {
+		val, err := util.GetStorageAt(context.TODO(), v.app.rpc, v.address, s.Slot, "latest")
+		if err != nil {
+			v.app.log.Error("failed to read storage: ", err)
+			fmt.Fprintf(&b, "%s: %s\n", s.Name, v.app.theme.Failure.Render(cview.Escape(err.Error())))
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %s\n", s.Name, val.Address().Hex())
+	}
+	v.app.app.QueueUpdateDraw(func() {
+		v.output.SetText(b.String())
+	})
+}
+
+func formatStorageValue(val util.StorageValue) string {
+	return fmt.Sprintf("bytes32: %s\nuint: %s\naddress: %s\nbool: %t\n",
+		val.Bytes32(), val.Uint(), val.Address().Hex(), val.Bool())
+}
@@ -0,0 +1,266 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.rocketnine.space/tslocum/cview"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gdamore/tcell/v2"
+	"github.com/treethought/ethscan/util"
+)
+
+// Style is a cview style tag without the brackets, `fg:bg:attributes`,
+// e.g. `red`, `#ff8800::b` or `::r`. An empty style leaves text unstyled.
+type Style string
+
+// Render wraps the text in the style's tags
+func (s Style) Render(text string) string {
+	if s == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s]%s[-:-:-]", s, text)
+}
+
+// tcell converts the style for primitives which take colors rather than tags
+func (s Style) tcell() (fg, bg tcell.Color, attrs tcell.AttrMask) {
+	fg, bg = tcell.ColorDefault, tcell.ColorDefault
+	parts := strings.SplitN(string(s), ":", 3)
+	if parts[0] != "" && parts[0] != "-" {
+		fg = tcell.GetColor(parts[0])
+	}
+	if len(parts) > 1 && parts[1] != "" && parts[1] != "-" {
+		bg = tcell.GetColor(parts[1])
+	}
+	if len(parts) > 2 {
+		for _, a := range parts[2] {
+			attrs |= styleAttrs[a]
+		}
+	}
+	return fg, bg, attrs
+}
+
+var styleAttrs = map[rune]tcell.AttrMask{
+	'b': tcell.AttrBold,
+	'd': tcell.AttrDim,
+	'l': tcell.AttrBlink,
+	'r': tcell.AttrReverse,
+	'u': tcell.AttrUnderline,
+}
+
+func (s Style) validate() error {
+	parts := strings.SplitN(string(s), ":", 3)
+	for _, color := range parts[:min(len(parts), 2)] {
+		if color != "" && color != "-" && tcell.GetColor(color) == tcell.ColorDefault {
+			return fmt.Errorf("unknown color %q", color)
+		}
+	}
+	if len(parts) > 2 {
+		for _, a := range parts[2] {
+			if _, ok := styleAttrs[a]; !ok && a != '-' {
+				return fmt.Errorf("unknown attribute %q", a)
+			}
+		}
+	}
+	return nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Theme maps semantic roles to styles, so views never hardcode colors.
+type Theme struct {
+	Success  Style
+	Failure  Style
+	Warning  Style
+	Contract Style
+	// Label is used for addresses with a known label
+	Label  Style
+	ENS    Style
+	Method Style
+	// Muted is used for secondary text such as line numbers
+	Muted Style
+	// Highlight marks notable values, such as changed storage or jump
+	// destinations
+	Highlight Style
+	// Selected is the style of the selected table row
+	Selected Style
+
+	// source highlighting
+	Keyword Style
+	Type    Style
+	String  Style
+	Number  Style
+	Comment Style
+}
+
+var themes = map[string]Theme{
+	"dark": {
+		Success:   "green",
+		Failure:   "red",
+		Warning:   "yellow",
+		Contract:  "red",
+		Label:     "orange",
+		ENS:       "blue",
+		Method:    "blue",
+		Muted:     "gray",
+		Highlight: "yellow",
+		Selected:  "blueviolet",
+		Keyword:   "fuchsia",
+		Type:      "aqua",
+		String:    "yellow",
+		Number:    "orange",
+		Comment:   "gray",
+	},
+	"light": {
+		Success:   "darkgreen",
+		Failure:   "darkred",
+		Warning:   "darkgoldenrod",
+		Contract:  "darkred",
+		Label:     "darkorange",
+		ENS:       "navy",
+		Method:    "mediumblue",
+		Muted:     "dimgray",
+		Highlight: "darkgoldenrod",
+		Selected:  "white:mediumblue",
+		Keyword:   "purple",
+		Type:      "teal",
+		String:    "darkgreen",
+		Number:    "saddlebrown",
+		Comment:   "dimgray",
+	},
+	"monochrome": {
+		Failure:   "::b",
+		Warning:   "::b",
+		Contract:  "::u",
+		Label:     "::u",
+		ENS:       "::u",
+		Muted:     "::d",
+		Highlight: "::b",
+		Selected:  "::r",
+		Keyword:   "::b",
+		Comment:   "::d",
+	},
+}
+
+// themeBase is the key of a custom theme naming the theme it extends
+const themeBase = "base"
+
+// fields maps the config keys of the theme to its styles
+func (t *Theme) fields() map[string]*Style {
+	return map[string]*Style{
+		"success":   &t.Success,
+		"failure":   &t.Failure,
+		"warning":   &t.Warning,
+		"contract":  &t.Contract,
+		"label":     &t.Label,
+		"ens":       &t.ENS,
+		"method":    &t.Method,
+		"muted":     &t.Muted,
+		"highlight": &t.Highlight,
+		"selected":  &t.Selected,
+		"keyword":   &t.Keyword,
+		"type":      &t.Type,
+		"string":    &t.String,
+		"number":    &t.Number,
+		"comment":   &t.Comment,
+	}
+}
+
+// NewTheme returns the named theme, either built in or one of the custom
+// themes. A custom theme overrides the styles of its base theme, dark by
+// default.
+func NewTheme(name string, custom map[string]map[string]string) (*Theme, error) {
+	if name == "" {
+		name = "dark"
+	}
+	return newTheme(name, custom, nil)
+}
+
+func newTheme(name string, custom map[string]map[string]string, seen []string) (*Theme, error) {
+	overrides, ok := custom[name]
+	for _, s := range seen {
+		if s == name {
+			// a custom theme may extend the built in theme of the same name
+			if _, builtin := themes[name]; !builtin {
+				return nil, fmt.Errorf("theme: %q extends itself through its base", name)
+			}
+			ok = false
+		}
+	}
+	if !ok {
+		t, ok := themes[name]
+		if !ok {
+			return nil, fmt.Errorf("theme: unknown theme %q, available: %s", name, strings.Join(themeNames(custom), ", "))
+		}
+		return &t, nil
+	}
+
+	base := overrides[themeBase]
+	if base == "" {
+		base = "dark"
+	}
+	t, err := newTheme(base, custom, append(seen, name))
+	if err != nil {
+		return nil, err
+	}
+	fields := t.fields()
+	for key, value := range overrides {
+		if key == themeBase {
+			continue
+		}
+		style, ok := fields[key]
+		if !ok {
+			return nil, fmt.Errorf("theme %s: unknown style %q", name, key)
+		}
+		if err := Style(value).validate(); err != nil {
+			return nil, fmt.Errorf("theme %s: %s: %w", name, key, err)
+		}
+		*style = Style(value)
+	}
+	return t, nil
+}
+
+func themeNames(custom map[string]map[string]string) []string {
+	var names []string
+	for name := range themes {
+		names = append(names, name)
+	}
+	for name := range custom {
+		if _, ok := themes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// addressName is the display name of an address and where it came from
+type addressName struct {
+	name string
+	kind util.AddressKind
+}
+
+// lookupAddress returns the display name of an address, it may block on
+// an ENS lookup
+func (a *App) lookupAddress(addr common.Address) addressName {
+	name, kind := util.LookupAddress(a.ctx, a.ens, a.chain, addr)
+	return addressName{name: name, kind: kind}
+}
+
+// renderAddressName renders a label or ENS name in the theme's style
+func (a *App) renderAddressName(n addressName) string {
+	name := cview.Escape(n.name)
+	switch n.kind {
+	case util.AddressLabel:
+		return a.theme.Label.Render(name)
+	case util.AddressENS:
+		return a.theme.ENS.Render(name)
+	}
+	return name
+}
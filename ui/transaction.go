@@ -3,10 +3,12 @@ package ui
 import (
 	"context"
 	"fmt"
+	"math/big"
 
 	"code.rocketnine.space/tslocum/cbind"
 	"code.rocketnine.space/tslocum/cview"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gdamore/tcell/v2"
 	"github.com/treethought/ethscan/util"
 )
 
@@ -24,10 +26,28 @@ func NewTransactionData(app *App, txn *types.Transaction) *TransactionData {
 		app:  app,
 		txn:  txn,
 	}
+	d.initBindings()
 	return d
 
 }
 
+func (d *TransactionData) initBindings() {
+	d.bindings = cbind.NewConfiguration()
+	d.app.keys.bind(d.bindings, ActionReplay, d.handleReplay)
+	d.app.keys.bind(d.bindings, ActionDebug, d.handleDebug)
+	d.app.keys.bind(d.bindings, ActionShowBlobs, d.handleBlobs)
+	d.SetInputCapture(d.bindings.Capture)
+}
+
+func (d *TransactionData) handleReplay(ev *tcell.EventKey) *tcell.EventKey {
+	if d.txn == nil || d.block == nil {
+		return nil
+	}
+	view := NewReplayView(d.app, d.block, d.txn)
+	d.app.app.SetRoot(view, true)
+	return nil
+}
+
 func (d *TransactionData) Update() {
 	txn := d.app.State.txn
 	d.block = d.app.State.block
@@ -35,44 +55,88 @@ func (d *TransactionData) Update() {
 }
 
 func (d *TransactionData) SetTransaction(txn *types.Transaction) {
-	if d.txn != nil && d.txn.Hash() == txn.Hash() {
+	if d.txn != nil && d.app.client.TxHash(d.txn) == d.app.client.TxHash(txn) {
 		return
 	}
 	d.app.app.QueueUpdateDraw(func() {
 		d.Clear()
 		d.txn = txn
-		d.render()
+		if txn != nil {
+			go d.load(d.app.ctx, txn)
+		}
 	})
 
 }
 
-func (d *TransactionData) render() {
-	if d.txn == nil {
-		d.Clear()
+func (d *TransactionData) handleDebug(ev *tcell.EventKey) *tcell.EventKey {
+	if d.txn == nil || d.block == nil {
+		return nil
+	}
+	view := NewDebuggerView(d.app, d.block, d.txn)
+	d.app.app.SetRoot(view, true)
+	return nil
+}
+
+func (d *TransactionData) handleBlobs(ev *tcell.EventKey) *tcell.EventKey {
+	if d.txn == nil || d.block == nil || len(d.txn.BlobHashes()) == 0 {
+		return nil
+	}
+	d.app.app.SetRoot(NewBlobsView(d.app, d.block, d.txn), true)
+	return nil
+}
+
+func blobGasPrice(rec *util.Receipt) *big.Int {
+	if rec.BlobGasPrice == nil {
+		return new(big.Int)
+	}
+	return rec.BlobGasPrice
+}
+
+// load fetches the receipt and sender of the transaction and the names of
+// its addresses off the ui goroutine, rendering them unless another
+// transaction was shown meanwhile
+func (d *TransactionData) load(ctx context.Context, txn *types.Transaction) {
+	rec, err := d.app.client.Receipt(ctx, d.app.client.TxHash(txn))
+	if err != nil {
+		d.app.notifyError("failed to get txn receipt", err)
 		return
 	}
-	rec, err := d.app.client.TransactionReceipt(context.TODO(), d.txn.Hash())
+	sender, err := d.app.senders.Sender(ctx, txn)
 	if err != nil {
-		d.app.log.Error("failed to get txn receipt: ", err)
+		d.app.log.Error("failed to get txn sender: ", err)
+	}
+	fromName := d.app.lookupAddress(sender)
+	var toName addressName
+	if txn.To() != nil {
+		toName = d.app.lookupAddress(*txn.To())
 	}
+	d.app.app.QueueUpdateDraw(func() {
+		if d.txn != txn {
+			return
+		}
+		d.render(rec, fromName, toName)
+	})
+}
 
-	msg, err := d.txn.AsMessage(d.app.signer, d.block.BaseFee())
-	if err != nil {
-		d.app.log.Error("failed to get txn as message: ", err)
+func (d *TransactionData) render(rec *util.Receipt, fromName, toName addressName) {
+	fees := util.GetFees(rec, d.txn, d.block.BaseFee())
+	ether := func(wei *big.Int) string {
+		return fmt.Sprintf("%s %s", util.WeiToEther(wei).String(), d.app.chain.Symbol())
 	}
 
 	meta := cview.NewList()
-	meta.SetTitle("meta")
+	meta.SetTitle(fmt.Sprintf("meta (hit `%s` to re-simulate, `%s` to debug)",
+		d.app.keys.Keys(ActionReplay), d.app.keys.Keys(ActionDebug)))
 	meta.SetBorder(true)
 
 	hash := cview.NewListItem("Hash")
-	hash.SetSecondaryText(d.txn.Hash().Hex())
+	hash.SetSecondaryText(d.app.client.TxHash(d.txn).Hex())
 	meta.AddItem(hash)
 
 	status := cview.NewListItem("Status")
 	statusText := "Success"
 	if rec.Status != 1 {
-		statusText = "[red]Failed[red]"
+		statusText = d.app.theme.Failure.Render("Failed")
 	}
 	status.SetSecondaryText(statusText)
 	meta.AddItem(status)
@@ -86,7 +150,7 @@ func (d *TransactionData) render() {
 	info.SetBorder(true)
 
 	from := cview.NewListItem("From")
-	from.SetSecondaryText(util.FormatAddress(d.app.client, msg.From()))
+	from.SetSecondaryText(d.app.renderAddressName(fromName))
 	info.AddItem(from)
 
 	var to *cview.ListItem
@@ -96,8 +160,8 @@ func (d *TransactionData) render() {
 	} else {
 		to = cview.NewListItem("To")
 	}
-	if msg.To() != nil {
-		to.SetSecondaryText(util.FormatAddress(d.app.client, *msg.To()))
+	if d.txn.To() != nil {
+		to.SetSecondaryText(d.app.renderAddressName(toName))
 	} else {
 		to.SetSecondaryText("none")
 	}
@@ -105,15 +169,12 @@ func (d *TransactionData) render() {
 
 	value := cview.NewListItem("Value")
 	val := util.WeiToEther(d.txn.Value())
-	valText := fmt.Sprintf("%s ETH", val.String())
+	valText := fmt.Sprintf("%s %s", val.String(), d.app.chain.Symbol())
 	value.SetSecondaryText(valText)
 	info.AddItem(value)
 
 	fee := cview.NewListItem("Transaction Fee")
-	feeWei := util.GetFee(rec, d.txn, d.block.BaseFee())
-	feeStr := fmt.Sprintf("%s ETH", util.WeiToEther(feeWei).String())
-
-	fee.SetSecondaryText(feeStr)
+	fee.SetSecondaryText(ether(fees.Total))
 	info.AddItem(fee)
 
 	gas := cview.NewList()
@@ -121,43 +182,58 @@ func (d *TransactionData) render() {
 	gas.SetBorder(true)
 
 	gasPrice := cview.NewListItem("Gas Price (Gwei)")
-	gasPrice.SetSecondaryText(util.WeiToGwei(msg.GasPrice()).String())
+	gasPrice.SetSecondaryText(util.WeiToGwei(fees.GasPrice).String())
 	gas.AddItem(gasPrice)
 
 	gasLimit := cview.NewListItem("Gas Limit")
-	gasLimit.SetSecondaryText(fmt.Sprint(msg.Gas()))
+	gasLimit.SetSecondaryText(fmt.Sprint(d.txn.Gas()))
 	gas.AddItem(gasLimit)
 
-	pctUsed := float64(rec.GasUsed) / float64(msg.Gas()) * float64(100)
+	pctUsed := float64(rec.GasUsed) / float64(d.txn.Gas()) * float64(100)
 	gasUsed := cview.NewListItem("Gas Used")
 	gasUsed.SetSecondaryText(fmt.Sprintf("%d (%.2f)%%", rec.GasUsed, pctUsed))
 	gas.AddItem(gasUsed)
 
-	if d.txn.Type() == 2 {
+	if d.txn.Type() == types.DynamicFeeTxType || d.txn.Type() == types.BlobTxType {
 		gasFees := cview.NewListItem("Gas Fees (Gwei)")
 		gasFeeTxt := fmt.Sprintf("Base: %.2f | Max: %.2f | Max Priority: %.2f",
 			util.WeiToGwei(d.block.BaseFee()),
-			util.WeiToGwei(msg.GasFeeCap()),
-			util.WeiToGwei(msg.GasTipCap()))
+			util.WeiToGwei(d.txn.GasFeeCap()),
+			util.WeiToGwei(d.txn.GasTipCap()))
 		gasFees.SetSecondaryText(gasFeeTxt)
 		gas.AddItem(gasFees)
 	}
 
+	burnt := cview.NewListItem("Burnt Fees")
+	burnt.SetSecondaryText(ether(fees.Burnt))
+	gas.AddItem(burnt)
+
+	tip := cview.NewListItem("Priority Fees")
+	tip.SetSecondaryText(ether(fees.Tip))
+	gas.AddItem(tip)
+
+	if d.txn.Type() == types.BlobTxType {
+		blobGas := cview.NewListItem("Blob Gas (Gwei)")
+		blobGas.SetSecondaryText(fmt.Sprintf("Used: %d | Price: %.4f | Max: %.4f", rec.BlobGasUsed,
+			util.WeiToGwei(blobGasPrice(rec)), util.WeiToGwei(d.txn.BlobGasFeeCap())))
+		gas.AddItem(blobGas)
+
+		blob := cview.NewListItem("Blob Fees")
+		blob.SetSecondaryText(ether(fees.Blob))
+		gas.AddItem(blob)
+	}
+
+	if rec.L1 != nil {
+		d.renderL1(gas, rec.L1, ether)
+	}
+
 	other := cview.NewList()
 	other.SetTitle("other")
 	other.SetBorder(true)
 
 	txnType := cview.NewListItem("Txn Type")
-	var txnTypeTxt string
-	switch d.txn.Type() {
-	case 0:
-		txnTypeTxt = "0 (Legacy)"
-	case 1:
-		txnTypeTxt = "1 (Contract Deployment)"
-	case 2:
-		txnTypeTxt = "2 (EIP-1559)"
-	}
-	txnType.SetSecondaryText(txnTypeTxt)
+	typ := d.app.client.TxType(d.txn)
+	txnType.SetSecondaryText(fmt.Sprintf("%d (%s)", typ, txnTypeName(typ)))
 
 	nonce := cview.NewListItem("Nonce")
 	nonce.SetSecondaryText(fmt.Sprint(d.txn.Nonce()))
@@ -169,6 +245,19 @@ func (d *TransactionData) render() {
 	other.AddItem(nonce)
 	other.AddItem(pos)
 
+	if rollup, ok := d.app.client.Rollup(d.txn); ok {
+		d.renderRollup(other, rollup, ether)
+	}
+
+	if hashes := d.txn.BlobHashes(); len(hashes) > 0 {
+		other.SetTitle(fmt.Sprintf("other (hit `%s` to fetch the blobs)", d.app.keys.Keys(ActionShowBlobs)))
+		for i, h := range hashes {
+			blob := cview.NewListItem(fmt.Sprintf("Blob %d", i))
+			blob.SetSecondaryText(h.Hex())
+			other.AddItem(blob)
+		}
+	}
+
 	data := cview.NewTextView()
 	data.SetTitle("Input Data")
 	data.SetBorder(true)
@@ -184,3 +273,52 @@ func (d *TransactionData) render() {
 	d.AddItem(other, 1, 2, 1, 2, 0, 0, false)
 
 }
+
+// renderL1 lists the layer 1 fee and gas rollups charge for posting the
+// transaction
+func (d *TransactionData) renderL1(gas *cview.List, l1 *util.L1Receipt, ether func(*big.Int) string) {
+	if l1.Fee != nil {
+		fee := cview.NewListItem("L1 Fee")
+		fee.SetSecondaryText(ether(l1.Fee))
+		gas.AddItem(fee)
+	}
+	if l1.GasUsed != nil {
+		text := fmt.Sprintf("Used: %s", l1.GasUsed)
+		if l1.GasPrice != nil {
+			text += fmt.Sprintf(" | Price: %.4f Gwei", util.WeiToGwei(l1.GasPrice))
+		}
+		if l1.BlobBaseFee != nil {
+			text += fmt.Sprintf(" | Blob Base Fee: %.4f Gwei", util.WeiToGwei(l1.BlobBaseFee))
+		}
+		if l1.FeeScalar != "" {
+			text += fmt.Sprintf(" | Scalar: %s", l1.FeeScalar)
+		}
+		l1Gas := cview.NewListItem("L1 Gas")
+		l1Gas.SetSecondaryText(text)
+		gas.AddItem(l1Gas)
+	}
+	if l1.BlockNumber != nil {
+		block := cview.NewListItem("L1 Block")
+		block.SetSecondaryText(l1.BlockNumber.String())
+		gas.AddItem(block)
+	}
+}
+
+// renderRollup lists the fields of deposits and system transactions
+func (d *TransactionData) renderRollup(other *cview.List, rollup *util.RollupTx, ether func(*big.Int) string) {
+	if rollup.Label() != "" {
+		kind := cview.NewListItem("Kind")
+		kind.SetSecondaryText(d.app.theme.Keyword.Render(rollup.Label()))
+		other.AddItem(kind)
+	}
+	if rollup.SourceHash != nil {
+		source := cview.NewListItem("Source Hash")
+		source.SetSecondaryText(rollup.SourceHash.Hex())
+		other.AddItem(source)
+	}
+	if rollup.Mint != nil && rollup.Mint.Sign() > 0 {
+		mint := cview.NewListItem("Mint")
+		mint.SetSecondaryText(ether(rollup.Mint))
+		other.AddItem(mint)
+	}
+}
@@ -2,8 +2,11 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"code.rocketnine.space/tslocum/cbind"
@@ -17,47 +20,155 @@ import (
 	"github.com/treethought/ethscan/util"
 )
 
-type abiMsg struct {
-	txn *types.Transaction
-	abi *abi.ABI
-	row int
+const (
+	// renderDelay coalesces the updates of rows loading in the background
+	renderDelay = 100 * time.Millisecond
+	// txnFetchers bounds the rows of a table loading at once
+	txnFetchers = 8
+)
+
+type methodKind int
+
+const (
+	methodCall methodKind = iota
+	methodDeployment
+	methodTransfer
+	methodDecoded
+	// methodSystem labels rollup deposits and system transactions
+	methodSystem
+)
+
+var txnTypes = map[uint8]string{
+	types.LegacyTxType:     "legacy",
+	types.AccessListTxType: "access-list",
+	types.DynamicFeeTxType: "dynamic-fee",
+	types.BlobTxType:       "blob",
+
+	util.DepositTxType:                 "deposit",
+	util.ArbitrumDepositTxType:         "arb-deposit",
+	util.ArbitrumUnsignedTxType:        "arb-unsigned",
+	util.ArbitrumContractTxType:        "arb-contract",
+	util.ArbitrumRetryTxType:           "arb-retry",
+	util.ArbitrumSubmitRetryableTxType: "arb-submit-retryable",
+	util.ArbitrumInternalTxType:        "arb-internal",
+	util.ArbitrumLegacyTxType:          "arb-legacy",
+}
+
+func txnTypeName(typ uint8) string {
+	if name, ok := txnTypes[typ]; ok {
+		return name
+	}
+	return fmt.Sprintf("type %d", typ)
+}
+
+// txnRow holds a transaction of the block along with the data loaded for
+// it in the background
+type txnRow struct {
+	index int
+	txn   *types.Transaction
+	// hash and typ are those of the rollup transaction a stand-in is for
+	hash common.Hash
+	typ  uint8
+	from common.Address
+
+	fromName, toName addressName
+
+	// loaded is set once the receipt was fetched, which may have failed
+	loaded  bool
+	receipt *util.Receipt
+
+	method     string
+	methodKind methodKind
+}
+
+func (r *txnRow) status() string {
+	switch {
+	case r.receipt == nil:
+		return "?"
+	case r.receipt.Status != 1:
+		return "failed"
+	}
+	return "success"
+}
+
+func (r *txnRow) txnType() string {
+	return txnTypeName(r.typ)
 }
 
-type ensMsg struct {
-	row  int
-	addr *common.Address
-	ens  string
+func (r *txnRow) gas() uint64 {
+	if r.receipt == nil {
+		return r.txn.Gas()
+	}
+	return r.receipt.GasUsed
+}
+
+// txnFields are the fields of the transactions filter
+var txnFields = map[string]filterField{
+	"position": {numeric: true},
+	"hash":     {},
+	"method":   {},
+	"from":     {},
+	"to":       {},
+	"address":  {},
+	"value":    {numeric: true},
+	"fee":      {numeric: true},
+	"gas":      {numeric: true},
+	"logs":     {numeric: true},
+	"status":   {},
+	"type":     {},
 }
 
+// txnSortColumns are the fields the transactions can be sorted by, cycled
+// through in order
+var txnSortColumns = []string{"position", "value", "fee", "gas"}
+
 type TransactionTable struct {
 	*cview.Table
 	app      *App
-	txs      []*types.Transaction
+	rows     []*txnRow
 	block    *types.Block
 	bindings *cbind.Configuration
 
-	abiChan chan abiMsg
-	ensChan chan ensMsg
+	filterExpr string
+	filter     filter
+	sortColumn string
+	sortDesc   bool
+	shown      int
+
+	// query is the incremental search
+	query   string
+	matches []int
+
+	// rows load in the background, guarded by mu until rendered
+	mu      sync.Mutex
+	pending bool
+	sem     chan struct{}
+
+	// position to restore once its row has loaded
+	restoreRow    int
+	restoreOffset int
+
+	// changed is called after the rows are rendered
+	changed func()
 }
 
 func NewTransactionTable(app *App, block *types.Block) *TransactionTable {
 	table := &TransactionTable{
-		Table:   cview.NewTable(),
-		txs:     nil,
-		abiChan: make(chan abiMsg),
-		app:     app,
-		block:   block,
+		Table:      cview.NewTable(),
+		app:        app,
+		block:      block,
+		sortColumn: "position",
+		sem:        make(chan struct{}, txnFetchers),
 	}
 	table.SetBorder(true)
 	table.SetTitle("Transactions")
 
-	table.setHeader()
+	table.SetFixed(1, 0)
 	table.SetSelectable(true, false)
+	table.SetSelectedStyle(app.theme.Selected.tcell())
 
 	table.initBindings()
 
-	go table.handleAbis(context.TODO())
-
 	table.SetSelectedFunc(func(row, _ int) {
 		if row == 0 {
 			return
@@ -65,15 +176,16 @@ func NewTransactionTable(app *App, block *types.Block) *TransactionTable {
 
 		txn := table.getCurrentRef()
 		if txn == nil {
-			log.Fatal("reference was not a txn")
+			return
 		}
 
-		table.app.log.Debug("Row reference txn hash: ", txn.Hash().String())
+		table.app.log.Debug("Row reference txn hash: ", table.app.client.TxHash(txn).String())
 		table.app.State.SetBlock(block)
 		table.app.State.SetTxn(txn)
 		table.app.ShowTransactonData(txn)
 	})
 
+	table.render()
 	go table.getTransactions()
 	return table
 }
@@ -81,26 +193,63 @@ func NewTransactionTable(app *App, block *types.Block) *TransactionTable {
 func (t *TransactionTable) initBindings() {
 	t.bindings = cbind.NewConfiguration()
 	t.SetInputCapture(t.bindings.Capture)
-	t.bindings.SetRune(tcell.ModNone, 'o', t.handleOpen)
-	t.bindings.SetRune(tcell.ModNone, 'y', t.handleCopy)
-	t.bindings.SetRune(tcell.ModNone, 'c', t.handleContract)
+	t.app.keys.bind(t.bindings, ActionOpenBrowser, t.handleOpen)
+	t.app.keys.bind(t.bindings, ActionCopy, t.handleCopy)
+	t.app.keys.bind(t.bindings, ActionShowContract, t.handleContract)
+	t.app.keys.bind(t.bindings, ActionSort, t.handleSort)
+	t.app.keys.bind(t.bindings, ActionReverseSort, t.handleReverseSort)
+	t.app.keys.bind(t.bindings, ActionFilter, t.handleFilter)
+	t.app.keys.bind(t.bindings, ActionFind, t.handleFind)
+	t.app.keys.bind(t.bindings, ActionFindNext, t.handleFindNext)
+	t.app.keys.bind(t.bindings, ActionFindPrev, t.handleFindPrev)
 
 }
 
-func (t *TransactionTable) getCurrentRef() *types.Transaction {
+func (t *TransactionTable) Position() (int, int) {
 	row, _ := t.GetSelection()
-	ref := t.GetCell(row, 0).GetReference()
-	txn, ok := ref.(*types.Transaction)
-	if !ok {
+	offset, _ := t.GetOffset()
+	return row, offset
+}
+
+// SetPosition selects the row, or waits for it to load
+func (t *TransactionTable) SetPosition(row, offset int) {
+	if row < t.GetRowCount() && t.GetCell(row, 0).GetReference() != nil {
+		t.Select(row, 0)
+		t.SetOffset(offset, 0)
+		return
+	}
+	t.restoreRow = row
+	t.restoreOffset = offset
+}
+
+func (t *TransactionTable) getSelectedRow() *txnRow {
+	row, _ := t.GetSelection()
+	if row <= 0 || row >= t.GetRowCount() {
+		return nil
+	}
+	r, _ := t.GetCell(row, 0).GetReference().(*txnRow)
+	return r
+}
+
+func (t *TransactionTable) getCurrentRef() *types.Transaction {
+	r := t.getSelectedRow()
+	if r == nil {
 		t.app.log.Error("failed to get txn ref")
 		return nil
 	}
-	return txn
+	return r.txn
 }
 
 func (t *TransactionTable) handleOpen(ev *tcell.EventKey) *tcell.EventKey {
 	cur := t.getCurrentRef()
-	url := fmt.Sprintf("https://etherscan.io/tx/%s", cur.Hash().Hex())
+	if cur == nil {
+		return nil
+	}
+	url, err := t.app.chain.ExplorerURL(t.app.chain.TxURL, t.app.client.TxHash(cur).Hex())
+	if err != nil {
+		t.app.notifyError("", err)
+		return nil
+	}
 	util.Openbrowser(url)
 	return nil
 
@@ -111,10 +260,12 @@ func (t *TransactionTable) handleCopy(ev *tcell.EventKey) *tcell.EventKey {
 	if txn == nil {
 		return nil
 	}
-	err := clipboard.WriteAll(txn.Hash().String())
+	err := clipboard.WriteAll(t.app.client.TxHash(txn).String())
 	if err != nil {
-		log.Fatal(err)
+		t.app.notifyError("failed to copy", err)
+		return nil
 	}
+	t.app.notify("copied transaction hash")
 	return nil
 }
 
@@ -124,154 +275,511 @@ func (t *TransactionTable) handleContract(ev *tcell.EventKey) *tcell.EventKey {
 		return nil
 	}
 
-	t.app.log.Debug("Row reference txn hash: ", txn.Hash().String())
+	t.app.log.Debug("Row reference txn hash: ", t.app.client.TxHash(txn).String())
+	if txn.To() == nil {
+		return nil
+	}
 	t.app.State.SetContract(txn.To())
-	t.app.ShowContractData(txn)
+	t.app.ShowContractData(*txn.To())
 
 	return nil
 }
 
-func (t *TransactionTable) getTransactions() {
-	if t.block == nil {
-		return
+// handleSort sorts by the next sortable column
+func (t *TransactionTable) handleSort(ev *tcell.EventKey) *tcell.EventKey {
+	next := 0
+	for i, c := range txnSortColumns {
+		if c == t.sortColumn {
+			next = (i + 1) % len(txnSortColumns)
+		}
 	}
-	for i, txn := range t.block.Transactions() {
-		t.addTxn(context.TODO(), i+1, txn)
+	t.sortColumn = txnSortColumns[next]
+	t.render()
+	return nil
+}
+
+func (t *TransactionTable) handleReverseSort(ev *tcell.EventKey) *tcell.EventKey {
+	t.sortDesc = !t.sortDesc
+	t.render()
+	return nil
+}
+
+func (t *TransactionTable) handleFilter(ev *tcell.EventKey) *tcell.EventKey {
+	t.app.cmdBar.Prompt(strings.TrimSpace("filter " + t.filterExpr))
+	return nil
+}
+
+func (t *TransactionTable) handleFind(ev *tcell.EventKey) *tcell.EventKey {
+	t.app.cmdBar.ActivateFind(t)
+	return nil
+}
+
+func (t *TransactionTable) handleFindNext(ev *tcell.EventKey) *tcell.EventKey {
+	t.jumpToMatch(1)
+	return nil
+}
+
+func (t *TransactionTable) handleFindPrev(ev *tcell.EventKey) *tcell.EventKey {
+	t.jumpToMatch(-1)
+	return nil
+}
+
+// Filter returns the current filter expression
+func (t *TransactionTable) Filter() string {
+	return t.filterExpr
+}
+
+// SetFilter shows only the transactions matching the expression, such as
+// `from = binance, value >= 1, status = failed`
+func (t *TransactionTable) SetFilter(expr string) error {
+	f, err := parseFilter(expr, txnFields)
+	if err != nil {
+		return err
 	}
+	t.filterExpr = expr
+	t.filter = f
+	t.render()
+	return nil
+}
 
+// SortBy sorts the transactions by a field
+func (t *TransactionTable) SortBy(name string, desc bool) error {
+	field, _, ok := lookupField(name, txnFields)
+	if !ok {
+		return fmt.Errorf("unknown field %q, available: %s", name, strings.Join(fieldNames(txnFields), ", "))
+	}
+	t.sortColumn = field
+	t.sortDesc = desc
+	t.render()
+	return nil
 }
 
-func (t TransactionTable) setHeader() {
-	// w, _ := t.app.app.GetScreen().Size()
-	t.SetCell(0, 0, cview.NewTableCell("Txn Hash"))
-	t.SetCell(0, 1, cview.NewTableCell("Method"))
-	t.SetCell(0, 2, cview.NewTableCell("Age"))
-	t.SetCell(0, 3, cview.NewTableCell("From"))
-	t.SetCell(0, 4, cview.NewTableCell("To"))
-	t.SetCell(0, 5, cview.NewTableCell("Value (Eth)"))
-	t.SetCell(0, 6, cview.NewTableCell("Fee (Eth)"))
-	t.SetCell(0, 7, cview.NewTableCell("Logs"))
-	t.SetCell(0, 8, cview.NewTableCell("Status"))
-	t.SetFixed(1, 0)
+// SortColumns lists the fields which can be sorted by
+func (t *TransactionTable) SortColumns() []string {
+	return fieldNames(txnFields)
+}
 
+// Find selects the first transaction from the selection on whose hash,
+// addresses or method contain the query
+func (t *TransactionTable) Find(query string) {
+	t.query = strings.ToLower(query)
+	t.mu.Lock()
+	t.findMatches()
+	t.mu.Unlock()
+	t.jumpToMatch(0)
+	t.renderTitle()
 }
 
-func (t TransactionTable) addTxn(ctx context.Context, row int, txn *types.Transaction) {
-	if txn == nil {
+func (t *TransactionTable) findMatches() {
+	t.matches = nil
+	if t.query == "" {
 		return
 	}
+	for row := 1; row < t.GetRowCount(); row++ {
+		r, ok := t.GetCell(row, 0).GetReference().(*txnRow)
+		if !ok {
+			continue
+		}
+		for _, s := range t.searchTexts(r) {
+			if strings.Contains(strings.ToLower(s), t.query) {
+				t.matches = append(t.matches, row)
+				break
+			}
+		}
+	}
+}
 
-	msg, err := txn.AsMessage(t.app.signer, t.block.BaseFee())
-	if err != nil {
-		t.app.log.Error("failed to get txn as message: ", err)
+func (t *TransactionTable) searchTexts(r *txnRow) []string {
+	texts := []string{r.hash.Hex(), r.from.Hex(), r.fromName.name, r.toName.name, r.method}
+	if r.txn.To() != nil {
+		texts = append(texts, r.txn.To().Hex())
+	}
+	return texts
+}
+
+// jumpToMatch selects the next match in the direction, or the first match
+// from the selection on when dir is 0
+func (t *TransactionTable) jumpToMatch(dir int) {
+	if len(t.matches) == 0 {
+		return
+	}
+	cur, _ := t.GetSelection()
+	switch dir {
+	case 0:
+		for _, row := range t.matches {
+			if row >= cur {
+				t.Select(row, 0)
+				return
+			}
+		}
+		t.Select(t.matches[0], 0)
+	case 1:
+		for _, row := range t.matches {
+			if row > cur {
+				t.Select(row, 0)
+				return
+			}
+		}
+		t.Select(t.matches[0], 0)
+	default:
+		for i := len(t.matches) - 1; i >= 0; i-- {
+			if t.matches[i] < cur {
+				t.Select(t.matches[i], 0)
+				return
+			}
+		}
+		t.Select(t.matches[len(t.matches)-1], 0)
+	}
+}
+
+func (t *TransactionTable) getTransactions() {
+	if t.block == nil {
+		return
+	}
+	txns := t.block.Transactions()
+	t.mu.Lock()
+	for i, txn := range txns {
+		t.rows = append(t.rows, &txnRow{index: i, txn: txn, hash: t.app.client.TxHash(txn), typ: t.app.client.TxType(txn)})
 	}
+	rows := t.rows
+	t.mu.Unlock()
+	t.scheduleRender()
+
+	load := &txnLoad{abis: make(map[common.Address]*abiFetch)}
+	for _, r := range rows {
+		t.sem <- struct{}{}
+		go func(r *txnRow) {
+			defer func() { <-t.sem }()
+			t.loadTxn(context.TODO(), load, r)
+		}(r)
+	}
+}
+
+// txnLoad is shared by the rows of a table loading together, so that each
+// contract's abi is fetched once and a rate limit is reported once
+type txnLoad struct {
+	mu          sync.Mutex
+	abis        map[common.Address]*abiFetch
+	rateLimited sync.Once
+}
+
+type abiFetch struct {
+	once sync.Once
+	abi  *abi.ABI
+	err  error
+}
 
-	go func() {
-		from := util.FormatAddress(t.app.client, msg.From())
-		var to string
-		if txn.To() == nil {
-			to = ""
-		} else {
-			to = util.FormatAddress(t.app.client, *txn.To())
+// contractABI fetches the abi of a contract, or waits for the row already
+// fetching it
+func (t *TransactionTable) contractABI(load *txnLoad, addr common.Address) (*abi.ABI, error) {
+	load.mu.Lock()
+	f, ok := load.abis[addr]
+	if !ok {
+		f = &abiFetch{}
+		load.abis[addr] = f
+	}
+	load.mu.Unlock()
+
+	f.once.Do(func() {
+		f.abi, f.err = util.GetContractABI(addr.Hex(), t.app.chain.API)
+		switch {
+		case errors.Is(f.err, util.ErrRateLimited):
+			load.rateLimited.Do(func() {
+				t.app.notifyError("failed to get abi", f.err)
+			})
+		case f.err != nil:
+			t.app.log.Error("failed to get abi: ", f.err)
 		}
+	})
+	return f.abi, f.err
+}
+
+// update applies a change to a row from a background goroutine
+func (t *TransactionTable) update(f func()) {
+	t.mu.Lock()
+	f()
+	t.mu.Unlock()
+	t.scheduleRender()
+}
+
+// scheduleRender renders once the updates queued in the meantime are in
+func (t *TransactionTable) scheduleRender() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending {
+		return
+	}
+	t.pending = true
+	time.AfterFunc(renderDelay, func() {
 		t.app.app.QueueUpdateDraw(func() {
-			fromCell := t.GetCell(row, 3)
-			fromCell.SetText(from)
-			toCell := t.GetCell(row, 4)
-			toCell.SetText(to)
+			t.mu.Lock()
+			t.pending = false
+			t.mu.Unlock()
+			t.render()
 		})
-	}()
+	})
+}
 
-	receipt, err := t.app.client.TransactionReceipt(ctx, txn.Hash())
+func (t *TransactionTable) loadTxn(ctx context.Context, load *txnLoad, r *txnRow) {
+	sender, err := t.app.senders.Sender(ctx, r.txn)
 	if err != nil {
-		log.Fatal(err)
+		t.app.log.Error("failed to get txn sender: ", err)
 	}
+	t.update(func() { r.from = sender })
 
-	var method string
-	var toField string
+	receipt, err := t.app.client.Receipt(ctx, r.hash)
+	if err != nil {
+		t.app.notifyError(fmt.Sprintf("failed to get receipt of %s", r.hash.Hex()), err)
+	}
 
-	// contract deployment
-	if txn.To() == nil {
-		method = "[red]ContractDeployment[red]"
-		toField = ""
-	} else if len(txn.Data()) >= 4 {
-		toField = txn.To().Hex()
+	var method string
+	var kind methodKind
+	rollup, _ := t.app.client.Rollup(r.txn)
+	switch {
+	case rollup != nil && rollup.Label() != "":
+		method, kind = rollup.Label(), methodSystem
+	case r.txn.To() == nil:
+		// contract deployment
+		method, kind = "ContractDeployment", methodDeployment
+	case len(r.txn.Data()) >= 4:
 		// contract execution
-		method = common.Bytes2Hex(txn.Data()[:4])
-		go func() {
-			abi, err := util.GetContractABI(toField, t.app.config.EtherscanKey)
-			if err != nil {
-				t.app.log.Error("failed to get abi: ", err)
-			}
-			t.abiChan <- abiMsg{abi: abi, txn: txn, row: row}
-		}()
-	} else {
-		toField = txn.To().Hex()
+		method, kind = common.Bytes2Hex(r.txn.Data()[:4]), methodCall
+	default:
 		// basic eth transfer
-		method = fmt.Sprintf("[yellow]Transfer[yellow]")
+		method, kind = "Transfer", methodTransfer
 	}
 
-	blockTime := time.Unix(int64(t.block.Time()), 0)
-	age := time.Since(blockTime).Truncate(time.Second)
-
-	hash := truncate.Truncate(txn.Hash().String(), truncSize, "...", truncate.PositionMiddle)
+	t.update(func() {
+		r.loaded = true
+		r.receipt = receipt
+		r.method = method
+		r.methodKind = kind
+	})
 
-	statusText := "success"
-	if receipt.Status != 1 {
-		statusText = "[red]failed[red]"
+	from := t.app.lookupAddress(sender)
+	var to addressName
+	if r.txn.To() != nil {
+		to = t.app.lookupAddress(*r.txn.To())
 	}
-
-	t.app.app.QueueUpdateDraw(func() {
-		hashRefCell := cview.NewTableCell(hash)
-		hashRefCell.SetReference(txn)
-		t.SetCell(row, 0, hashRefCell)
-
-		methodCell := cview.NewTableCell(method)
-		t.SetCell(row, 1, methodCell)
-		t.SetCell(row, 2, cview.NewTableCell(age.String()))
-		t.SetCell(row, 3, cview.NewTableCell(msg.From().Hex()))
-		t.SetCell(row, 4, cview.NewTableCell(toField))
-		t.SetCell(row, 5, cview.NewTableCell(util.WeiToEther(txn.Value()).String()))
-		fee := util.GetFee(receipt, txn, t.block.BaseFee())
-		t.SetCell(row, 6, cview.NewTableCell(util.WeiToEther(fee).String()))
-		t.SetCell(row, 7, cview.NewTableCell(fmt.Sprint(len(receipt.Logs))))
-		t.SetCell(row, 8, cview.NewTableCell(statusText))
+	t.update(func() {
+		r.fromName = from
+		r.toName = to
 	})
-	// go t.resolveAddresses(row)
-}
 
-func (t *TransactionTable) resolveAddresses(row int) {
-	t.app.log.Info("resolving row: ", row)
-	from := t.GetCell(row, 3)
-	to := t.GetCell(row, 4)
-	t.app.app.QueueUpdateDraw(func() {
-		from.SetText(util.FormatAddress(t.app.client, common.HexToAddress(from.GetText())))
-		to.SetText(util.FormatAddress(t.app.client, common.HexToAddress(to.GetText())))
-	})
+	if kind == methodCall {
+		t.decodeMethod(load, r)
+	}
 }
-func (t *TransactionTable) setMethod(m abiMsg) {
-	if m.abi == nil || len(m.txn.Data()) == 0 {
+
+func (t *TransactionTable) decodeMethod(load *txnLoad, r *txnRow) {
+	contractABI, err := t.contractABI(load, *r.txn.To())
+	if err != nil {
 		return
 	}
-	method, _, err := util.DecodeTransactionInputData(m.abi, m.txn.Data())
+	method, _, err := util.DecodeTransactionInputData(contractABI, r.txn.Data())
 	if err != nil {
 		t.app.log.Error("failed to decode txn input data: ", err)
+		return
 	}
+	t.update(func() {
+		r.method = method
+		r.methodKind = methodDecoded
+	})
+}
 
-	cell := t.GetCell(m.row, 1)
-	cell.SetText(fmt.Sprintf("[blue]%s[blue]", method))
+// value returns the field of a row for filtering and sorting
+func (t *TransactionTable) value(r *txnRow, field string) filterValue {
+	addr := func(a *common.Address, name addressName) filterValue {
+		if a == nil {
+			return textValue("deployment")
+		}
+		return textValue(a.Hex(), name.name)
+	}
+	switch field {
+	case "position":
+		return numValue(float64(r.index))
+	case "hash":
+		return textValue(r.hash.Hex())
+	case "method":
+		return textValue(r.method)
+	case "from":
+		return addr(&r.from, r.fromName)
+	case "to":
+		return addr(r.txn.To(), r.toName)
+	case "address":
+		from, to := addr(&r.from, r.fromName), addr(r.txn.To(), r.toName)
+		return textValue(append(from.texts, to.texts...)...)
+	case "value":
+		return numValue(bigFloat(util.WeiToEther(r.txn.Value())))
+	case "fee":
+		if r.receipt == nil {
+			return filterValue{}
+		}
+		return numValue(bigFloat(util.WeiToEther(util.GetFee(r.receipt, r.txn, t.block.BaseFee()))))
+	case "gas":
+		return numValue(float64(r.gas()))
+	case "logs":
+		if r.receipt == nil {
+			return filterValue{}
+		}
+		return numValue(float64(len(r.receipt.Logs)))
+	case "status":
+		return textValue(r.status())
+	case "type":
+		return textValue(r.txnType(), fmt.Sprint(r.typ))
+	}
+	return filterValue{}
+}
 
+func (t *TransactionTable) setHeader() {
+	// w, _ := t.app.app.GetScreen().Size()
+	titles := []string{"#", "Txn Hash", "Method", "Age", "From", "To",
+		fmt.Sprintf("Value (%s)", t.app.chain.Symbol()), fmt.Sprintf("Fee (%s)", t.app.chain.Symbol()),
+		"Gas", "Logs", "Status"}
+	sortCol := map[string]int{"position": 0, "hash": 1, "method": 2, "from": 4, "to": 5,
+		"value": 6, "fee": 7, "gas": 8, "logs": 9, "status": 10}
+	for col, title := range titles {
+		if c, ok := sortCol[t.sortColumn]; ok && c == col {
+			if t.sortDesc {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		cell := cview.NewTableCell(title)
+		cell.SetSelectable(false)
+		t.SetCell(0, col, cell)
+	}
 }
 
-func (t *TransactionTable) handleAbis(ctx context.Context) {
-	for {
-		select {
-		case m := <-t.abiChan:
-			t.app.log.Debug("received abi for addr: %s", m.txn.To().String())
-			t.setMethod(m)
-		case <-ctx.Done():
-			return
+// visible returns the rows matching the filter in sort order
+func (t *TransactionTable) visible() []*txnRow {
+	var rows []*txnRow
+	for _, r := range t.rows {
+		if t.filter.match(func(field string) filterValue { return t.value(r, field) }) {
+			rows = append(rows, r)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := t.value(rows[i], t.sortColumn), t.value(rows[j], t.sortColumn)
+		if t.sortDesc {
+			return b.less(a)
+		}
+		return a.less(b)
+	})
+	return rows
+}
+
+func (t *TransactionTable) renderAddress(a common.Address, name addressName) string {
+	if name.name == "" {
+		return a.Hex()
+	}
+	return t.app.renderAddressName(name)
+}
+
+func (t *TransactionTable) renderMethod(r *txnRow) string {
+	switch r.methodKind {
+	case methodDeployment:
+		return t.app.theme.Contract.Render(r.method)
+	case methodTransfer, methodDecoded:
+		return t.app.theme.Method.Render(cview.Escape(r.method))
+	case methodSystem:
+		return t.app.theme.Keyword.Render(r.method)
+	}
+	return r.method
+}
+
+// render redraws the rows, keeping the selected transaction selected
+func (t *TransactionTable) render() {
+	t.renderRows()
+	if t.changed != nil {
+		t.changed()
+	}
+}
+
+func (t *TransactionTable) renderRows() {
+	selected := t.getSelectedRow()
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
+	t.Clear()
+	t.setHeader()
+
+	var age string
+	if t.block != nil {
+		age = time.Since(time.Unix(int64(t.block.Time()), 0)).Truncate(time.Second).String()
+	}
+
+	rows := t.visible()
+	for i, r := range rows {
+		row := i + 1
+		hash := truncate.Truncate(r.hash.String(), truncSize, "...", truncate.PositionMiddle)
+		to := ""
+		if r.txn.To() != nil {
+			to = t.renderAddress(*r.txn.To(), r.toName)
+		}
+		fee, logs, status := "?", "?", r.status()
+		if r.receipt != nil {
+			fee = util.WeiToEther(util.GetFee(r.receipt, r.txn, t.block.BaseFee())).String()
+			logs = fmt.Sprint(len(r.receipt.Logs))
+			if r.receipt.Status != 1 {
+				status = t.app.theme.Failure.Render(status)
+			}
+		}
+		from := ""
+		if r.from != (common.Address{}) {
+			from = t.renderAddress(r.from, r.fromName)
 		}
+
+		indexCell := cview.NewTableCell(fmt.Sprint(r.index))
+		// set the row's reference
+		indexCell.SetReference(r)
+		t.SetCell(row, 0, indexCell)
+		t.SetCell(row, 1, cview.NewTableCell(hash))
+		t.SetCell(row, 2, cview.NewTableCell(t.renderMethod(r)))
+		t.SetCell(row, 3, cview.NewTableCell(age))
+		t.SetCell(row, 4, cview.NewTableCell(from))
+		t.SetCell(row, 5, cview.NewTableCell(to))
+		t.SetCell(row, 6, cview.NewTableCell(util.WeiToEther(r.txn.Value()).String()))
+		t.SetCell(row, 7, cview.NewTableCell(fee))
+		t.SetCell(row, 8, cview.NewTableCell(fmt.Sprint(r.gas())))
+		t.SetCell(row, 9, cview.NewTableCell(logs))
+		t.SetCell(row, 10, cview.NewTableCell(status))
+
+		if r == selected {
+			t.Select(row, 0)
+		}
+		if row == t.restoreRow && r.loaded {
+			t.Select(row, 0)
+			t.SetOffset(t.restoreOffset, 0)
+			t.restoreRow = 0
+		}
+	}
+	t.shown = len(rows)
+	t.findMatches()
+	t.renderTitle()
+}
+
+// receipts returns the receipts loaded so far by transaction hash
+func (t *TransactionTable) receipts() map[common.Hash]*util.Receipt {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	receipts := make(map[common.Hash]*util.Receipt, len(t.rows))
+	for _, r := range t.rows {
+		if r.receipt != nil {
+			receipts[r.hash] = r.receipt
+		}
+	}
+	return receipts
+}
+
+func (t *TransactionTable) renderTitle() {
+	title := fmt.Sprintf("Transactions (%d)", t.shown)
+	if t.filterExpr != "" {
+		title = fmt.Sprintf("Transactions (%d of %d, filter: %s)", t.shown, len(t.rows), t.filterExpr)
+	}
+	if t.query != "" {
+		title += fmt.Sprintf(" find %q: %d matches", t.query, len(t.matches))
 	}
+	t.SetTitle(cview.Escape(title))
 }
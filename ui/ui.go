@@ -3,16 +3,14 @@ package ui
 import (
 	"context"
 	"log"
-	"math/big"
 	"os"
-	"strconv"
 	"sync"
 
 	"code.rocketnine.space/tslocum/cbind"
 	"code.rocketnine.space/tslocum/cview"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/gdamore/tcell/v2"
 	"github.com/kataras/golog"
 	"github.com/treethought/ethscan/util"
@@ -23,8 +21,12 @@ type State struct {
 	block           *types.Block
 	txn             *types.Transaction
 	contractAddress *common.Address
-	history         []string
-	currentView     string
+	address         *common.Address
+
+	// navigation stack, see nav.go
+	current Location
+	back    []Location
+	forward []Location
 }
 
 func (s *State) SetBlock(b *types.Block) {
@@ -43,28 +45,10 @@ func (s *State) SetContract(a *common.Address) {
 	s.contractAddress = a
 }
 
-func (s *State) SetView(p string) {
+func (s *State) SetAddress(a *common.Address) {
 	s.Lock()
 	defer s.Unlock()
-	s.history = append([]string{s.currentView}, s.history...)
-	s.currentView = p
-}
-
-func (s *State) RevertView() {
-	s.Lock()
-	defer s.Unlock()
-	if len(s.history) == 0 {
-		return
-	}
-	prev := s.history[0]
-	if len(s.history) == 1 {
-		s.history = []string{}
-		s.currentView = prev
-		return
-	}
-	s.history = s.history[1:]
-	s.currentView = prev
-	return
+	s.address = a
 }
 
 type View interface {
@@ -72,15 +56,30 @@ type View interface {
 }
 
 type App struct {
-	client   *ethclient.Client
+	// the connection to the current chain, replaced when switching chains
+	chain    *util.Chain
+	client   *util.Client
+	rpc      *rpc.Client
+	ctx      context.Context
+	cancel   context.CancelFunc
 	app      *cview.Application
 	root     *cview.TabbedPanels
+	layout   *cview.Flex
+	cmdBar   *CommandBar
+	crumbs   *cview.TextView
+	status   *StatusBar
+	logs     *logBuffer
+	resolver *util.Resolver
+	ens      *util.ENS
+	beacon   *util.BeaconAPI
 	focus    *cview.FocusManager
 	bindings *cbind.Configuration
+	keys     Keymap
+	theme    *Theme
 	broker   *util.Broker
 	views    map[string]View
 	log      *golog.Logger
-	signer   types.Signer
+	senders  *util.Senders
 	State    *State
 	config   *Config
 }
@@ -88,7 +87,13 @@ type App struct {
 func NewApp(config *Config) *App {
 	golog.SetLevel("debug")
 
-	client, err := ethclient.Dial(config.RpcUrl)
+	chain, err := config.startProfile()
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	conn, err := connect(ctx, config, chain)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -97,20 +102,32 @@ func NewApp(config *Config) *App {
 	if err != nil {
 		panic(err)
 	}
-	log := golog.New().SetOutput(logFile)
+	keys, err := NewKeymap(config.Keymap)
+	if err != nil {
+		log.Fatal(err)
+	}
+	theme, err := NewTheme(config.Theme, config.Themes)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	return &App{
-		client:   client,
+	logs := &logBuffer{}
+	log := golog.New().SetOutput(logFile).AddOutput(logs)
+
+	a := &App{
 		app:      cview.NewApplication(),
 		focus:    nil,
 		bindings: cbind.NewConfiguration(),
-		broker:   util.NewBroker(client),
+		keys:     keys,
+		theme:    theme,
 		views:    make(map[string]View),
 		log:      log,
-		signer:   util.GetSigner(context.TODO(), client),
+		logs:     logs,
 		State:    &State{},
 		config:   config,
 	}
+	a.use(conn)
+	return a
 }
 
 func (app *App) initBlockData() *cview.Flex {
@@ -135,7 +152,7 @@ func (app *App) initBlockFeed() *cview.Flex {
 	wrap.SetBackgroundColor(tcell.ColorDefault)
 	wrap.SetDirection(cview.FlexRow)
 	wrap.AddItem(blockFeed, 0, 1, true)
-	go blockFeed.watch(context.TODO())
+	go blockFeed.watch(app.ctx)
 
 	return wrap
 
@@ -177,6 +194,51 @@ func (app *App) initContractData() *cview.Flex {
 
 }
 
+func (app *App) initAddressData() *cview.Flex {
+	app.log.Debug("initializing address data layout")
+
+	addressData := NewAddressData(app)
+	app.views["address"] = addressData
+
+	wrap := cview.NewFlex()
+	wrap.SetBackgroundTransparent(false)
+	wrap.SetBackgroundColor(tcell.ColorDefault)
+	wrap.SetDirection(cview.FlexRow)
+	wrap.AddItem(addressData, 0, 1, true)
+
+	return wrap
+}
+
+func (app *App) initMempool() *cview.Flex {
+	app.log.Debug("initializing mempool layout")
+
+	mempool := NewMempoolTable(app)
+	app.views["mempool"] = mempool
+
+	wrap := cview.NewFlex()
+	wrap.SetBackgroundTransparent(false)
+	wrap.SetBackgroundColor(tcell.ColorDefault)
+	wrap.SetDirection(cview.FlexRow)
+	wrap.AddItem(mempool, 0, 1, true)
+
+	return wrap
+}
+
+func (app *App) initGas() *cview.Flex {
+	app.log.Debug("initializing gas layout")
+
+	gas := NewGasView(app)
+	app.views["gas"] = gas
+
+	wrap := cview.NewFlex()
+	wrap.SetBackgroundTransparent(false)
+	wrap.SetBackgroundColor(tcell.ColorDefault)
+	wrap.SetDirection(cview.FlexRow)
+	wrap.AddItem(gas, 0, 1, true)
+
+	return wrap
+}
+
 func (app *App) initViews() {
 	app.log.Debug("initializing views")
 
@@ -184,6 +246,9 @@ func (app *App) initViews() {
 	blockData := app.initBlockData()
 	txnData := app.initTxnData()
 	contractData := app.initContractData()
+	addressData := app.initAddressData()
+	mempool := app.initMempool()
+	gas := app.initGas()
 
 	dataPanels := cview.NewTabbedPanels()
 	dataPanels.SetTitle("panels")
@@ -191,6 +256,9 @@ func (app *App) initViews() {
 	dataPanels.AddTab("blockData", "block data", blockData)
 	dataPanels.AddTab("txnData", "txn", txnData)
 	dataPanels.AddTab("contract", "contract", contractData)
+	dataPanels.AddTab("address", "address", addressData)
+	dataPanels.AddTab("mempool", "mempool", mempool)
+	dataPanels.AddTab("gas", "gas", gas)
 	dataPanels.SetCurrentTab("blockFeed")
 	dataPanels.SetBorder(false)
 	dataPanels.SetPadding(0, 0, 0, 0)
@@ -201,22 +269,41 @@ func (app *App) initViews() {
 	dataPanels.SetTabSwitcherAfterContent(true)
 
 	app.root = dataPanels
+
+	layout := cview.NewFlex()
+	layout.SetDirection(cview.FlexRow)
+	layout.AddItem(app.crumbs, 1, 0, false)
+	layout.AddItem(dataPanels, 0, 1, true)
+	layout.AddItem(app.cmdBar, 1, 0, false)
+	layout.AddItem(app.status, 1, 0, false)
+	app.layout = layout
+
 	app.log.Debug("views ready")
 	app.ShowView("blockFeed")
 }
 
 func (a *App) setBindings() {
-	a.bindings.SetKey(tcell.ModNone, tcell.KeyEsc, func(ev *tcell.EventKey) *tcell.EventKey {
+	a.keys.bind(a.bindings, ActionBack, a.handleBack)
+	a.keys.bind(a.bindings, ActionForward, a.handleForward)
+	a.keys.bind(a.bindings, ActionCommand, func(ev *tcell.EventKey) *tcell.EventKey {
 		if !a.root.HasFocus() {
 			return ev
 		}
-
-		a.State.RevertView()
-		if a.State.currentView == "" {
-			a.root.SetCurrentTab("blockFeed")
-			return nil
+		a.cmdBar.Activate()
+		return nil
+	})
+	a.keys.bind(a.bindings, ActionSearch, func(ev *tcell.EventKey) *tcell.EventKey {
+		if !a.root.HasFocus() || a.keys.shadowed(tabScopes[a.State.current.View], ev) {
+			return ev
+		}
+		a.cmdBar.ActivateSearch()
+		return nil
+	})
+	a.keys.bind(a.bindings, ActionHelp, func(ev *tcell.EventKey) *tcell.EventKey {
+		if !a.root.HasFocus() {
+			return ev
 		}
-		a.root.SetCurrentTab(a.State.currentView)
+		a.showHelp(tabScopes[a.State.current.View], a.layout)
 		return nil
 	})
 
@@ -235,9 +322,22 @@ func (a *App) ShowBlockData(b *types.Block) {
 	a.ShowView("blockData")
 }
 
+// ShowView switches to the view, recording the current state as a new
+// location in the navigation history
 func (a *App) ShowView(v string) {
+	a.savePosition()
+	loc := a.State.location(v)
+	if loc.Txn != nil {
+		loc.TxnHash = a.client.TxHash(loc.Txn)
+	}
+	a.State.Push(loc)
 	a.root.SetCurrentTab(v)
-	a.State.SetView(v)
+	a.renderBreadcrumbs()
+}
+
+// currentView returns the view of the current tab
+func (a *App) currentView() View {
+	return a.views[a.State.current.View]
 }
 
 func (a *App) ShowBlocks() {
@@ -246,105 +346,56 @@ func (a *App) ShowBlocks() {
 }
 
 func (a *App) ShowTransactonData(txn *types.Transaction) {
-	a.log.Info("showing txn data for: ", txn.Hash().String())
+	a.log.Info("showing txn data for: ", a.client.TxHash(txn).String())
 	txnData := a.views["txnData"]
 	txnLogs := a.views["txnLogs"]
 	txnData.Update()
 	txnLogs.Update()
 	a.ShowView("txnData")
 }
-func (a *App) ShowContractData(txn *types.Transaction) {
-	a.log.Info("showing txn data for: ", txn.Hash().String())
+func (a *App) ShowContractData(addr common.Address) {
+	a.log.Info("showing contract data for: ", addr.Hex())
 	contractData := a.views["contract"]
 	contractData.Update()
 	a.ShowView("contract")
 }
 
-func (a *App) Init() {
-	a.app.EnableMouse(true)
-	a.initViews()
-	a.setBindings()
-	a.app.SetRoot(a.root, true)
-}
-
-func (a *App) startWithBlockByNum(num int) error {
-	big := big.NewInt(int64(num))
-	block, err := a.client.BlockByNumber(context.TODO(), big)
-	if err != nil {
-		return err
-	}
-	a.State.SetBlock(block)
-	a.State.SetTxn(nil)
-
-	a.ShowBlockData(block)
-	a.Start()
-	return nil
+func (a *App) ShowAddressData(addr common.Address) {
+	a.log.Info("showing address data for: ", addr.Hex())
+	addressData := a.views["address"]
+	addressData.Update()
+	a.ShowView("address")
 }
 
-func (a *App) startWithBlockByHash(hash string) error {
-	h := common.HexToHash(hash)
-	block, err := a.client.BlockByHash(context.TODO(), h)
-	if err != nil {
-		return err
-	}
-	a.State.SetBlock(block)
-	a.ShowBlockData(block)
-	a.Start()
-	return nil
-
+// initBars creates the bars around the views, which are kept when
+// switching chains
+func (a *App) initBars() {
+	a.cmdBar = NewCommandBar(a)
+	a.status = NewStatusBar(a)
+	a.crumbs = cview.NewTextView()
+	a.crumbs.SetDynamicColors(true)
+	a.crumbs.SetWrap(false)
 }
 
-func (a *App) startWithTxn(hash string) error {
-	ctx := context.TODO()
-	h := common.HexToHash(hash)
-
-	txn, _, err := a.client.TransactionByHash(context.Background(), h)
-	if err != nil {
-		return err
-	}
-	rec, err := a.client.TransactionReceipt(ctx, h)
-	if err != nil {
-		return err
-	}
-	block, err := a.client.BlockByHash(ctx, rec.BlockHash)
-	if err != nil {
-		return err
-	}
-	a.State.SetBlock(block)
-	a.State.SetTxn(txn)
-	a.ShowTransactonData(txn)
-	a.Start()
-	return nil
-
+func (a *App) Init() {
+	a.app.EnableMouse(true)
+	a.initBars()
+	a.initViews()
+	a.setBindings()
+	a.app.SetRoot(a.layout, true)
 }
 
+// StartWith runs the app opening the block, transaction or address the
+// reference resolves to
 func (a *App) StartWith(ref string) {
-	num, err := strconv.Atoi(ref)
-	if err == nil {
-		err = a.startWithBlockByNum(num)
-		if err != nil {
-			log.Fatal(err)
-		}
-	}
-
-	err = a.startWithBlockByHash(ref)
-	if err == nil {
-		return
-	}
-
-	err = a.startWithTxn(ref)
-	if err == nil {
-		return
-	}
-
-	log.Fatal("failed to get block or txn with hash: ", ref)
-
+	a.cmdBar.Search(ref)
+	a.Start()
 }
 
 func (a *App) Start() {
 	defer a.app.HandlePanic()
 
-	go a.broker.ListenForBlocks(context.TODO())
+	a.listen()
 
 	if err := a.app.Run(); err != nil {
 		log.Fatal(err)
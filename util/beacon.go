@@ -0,0 +1,172 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// ErrNoBeaconAPI is returned when the chain has no beacon api configured
+var ErrNoBeaconAPI = errors.New("no beacon api configured for this chain")
+
+// Finality is how settled a slot is on the beacon chain
+type Finality string
+
+const (
+	Finalized Finality = "finalized"
+	Justified Finality = "justified"
+	Pending   Finality = "pending"
+)
+
+// BeaconInfo is the beacon chain's view of an execution block
+type BeaconInfo struct {
+	Slot     uint64
+	Epoch    uint64
+	Proposer uint64
+	Finality Finality
+}
+
+// BeaconAPI is a client of the standard beacon node api
+type BeaconAPI struct {
+	URL    string
+	client *resty.Client
+
+	// the spec is fetched once
+	mu     sync.Mutex
+	cached *beaconSpec
+}
+
+func NewBeaconAPI(url string) *BeaconAPI {
+	return &BeaconAPI{URL: strings.TrimSuffix(url, "/"), client: resty.New()}
+}
+
+// the api encodes numbers as strings
+type beaconUint uint64
+
+func (u *beaconUint) UnmarshalJSON(input []byte) error {
+	n, err := strconv.ParseUint(strings.Trim(string(input), `"`), 10, 64)
+	*u = beaconUint(n)
+	return err
+}
+
+func (b *BeaconAPI) get(path string, result interface{}) error {
+	if b.URL == "" {
+		return ErrNoBeaconAPI
+	}
+	resp, err := b.client.R().SetResult(result).Get(b.URL + path)
+	if err != nil {
+		return err
+	}
+	if !resp.IsSuccess() {
+		return fmt.Errorf("beacon api %s: %s", path, resp.Status())
+	}
+	return nil
+}
+
+type beaconSpec struct {
+	genesisTime    uint64
+	secondsPerSlot uint64
+	slotsPerEpoch  uint64
+}
+
+func (b *BeaconAPI) spec() (*beaconSpec, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cached != nil {
+		return b.cached, nil
+	}
+
+	var genesis struct {
+		Data struct {
+			GenesisTime beaconUint `json:"genesis_time"`
+		} `json:"data"`
+	}
+	if err := b.get("/eth/v1/beacon/genesis", &genesis); err != nil {
+		return nil, err
+	}
+	var config struct {
+		Data struct {
+			SecondsPerSlot beaconUint `json:"SECONDS_PER_SLOT"`
+			SlotsPerEpoch  beaconUint `json:"SLOTS_PER_EPOCH"`
+		} `json:"data"`
+	}
+	if err := b.get("/eth/v1/config/spec", &config); err != nil {
+		return nil, err
+	}
+	if config.Data.SecondsPerSlot == 0 || config.Data.SlotsPerEpoch == 0 {
+		return nil, fmt.Errorf("beacon api returned an invalid spec")
+	}
+	b.cached = &beaconSpec{
+		genesisTime:    uint64(genesis.Data.GenesisTime),
+		secondsPerSlot: uint64(config.Data.SecondsPerSlot),
+		slotsPerEpoch:  uint64(config.Data.SlotsPerEpoch),
+	}
+	return b.cached, nil
+}
+
+// Slot returns the slot of an execution block by its timestamp
+func (b *BeaconAPI) Slot(timestamp uint64) (uint64, error) {
+	spec, err := b.spec()
+	if err != nil {
+		return 0, err
+	}
+	if timestamp < spec.genesisTime {
+		return 0, fmt.Errorf("block predates the beacon chain")
+	}
+	return (timestamp - spec.genesisTime) / spec.secondsPerSlot, nil
+}
+
+// BlockInfo looks up the slot of an execution block by its timestamp,
+// along with its proposer and finality
+func (b *BeaconAPI) BlockInfo(timestamp uint64) (*BeaconInfo, error) {
+	spec, err := b.spec()
+	if err != nil {
+		return nil, err
+	}
+	slot, err := b.Slot(timestamp)
+	if err != nil {
+		return nil, err
+	}
+	info := &BeaconInfo{Slot: slot, Epoch: slot / spec.slotsPerEpoch}
+
+	var header struct {
+		Data struct {
+			Header struct {
+				Message struct {
+					ProposerIndex beaconUint `json:"proposer_index"`
+				} `json:"message"`
+			} `json:"header"`
+		} `json:"data"`
+	}
+	if err := b.get(fmt.Sprintf("/eth/v1/beacon/headers/%d", info.Slot), &header); err != nil {
+		return nil, err
+	}
+	info.Proposer = uint64(header.Data.Header.Message.ProposerIndex)
+
+	var checkpoints struct {
+		Data struct {
+			Justified struct {
+				Epoch beaconUint `json:"epoch"`
+			} `json:"current_justified"`
+			Finalized struct {
+				Epoch beaconUint `json:"epoch"`
+			} `json:"finalized"`
+		} `json:"data"`
+	}
+	if err := b.get("/eth/v1/beacon/states/head/finality_checkpoints", &checkpoints); err != nil {
+		return nil, err
+	}
+	switch {
+	case info.Epoch < uint64(checkpoints.Data.Finalized.Epoch):
+		info.Finality = Finalized
+	case info.Epoch < uint64(checkpoints.Data.Justified.Epoch):
+		info.Finality = Justified
+	default:
+		info.Finality = Pending
+	}
+	return info, nil
+}
@@ -0,0 +1,60 @@
+package util
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// blobCommitmentVersion is the version byte of blob versioned hashes
+const blobCommitmentVersion = 0x01
+
+// BlobCount returns the number of blobs in a block, or -1 for blocks
+// before Cancun
+func BlobCount(header *types.Header) int {
+	if header.BlobGasUsed == nil {
+		return -1
+	}
+	return int(*header.BlobGasUsed / params.BlobTxBlobGasPerBlob)
+}
+
+// BlobSidecar is a blob along with its commitment, as served by the beacon
+// api
+type BlobSidecar struct {
+	Index      uint64
+	Blob       []byte
+	Commitment []byte
+	Proof      []byte
+}
+
+// VersionedHash returns the hash blob transactions refer to the blob by
+func (s *BlobSidecar) VersionedHash() common.Hash {
+	h := sha256.Sum256(s.Commitment)
+	h[0] = blobCommitmentVersion
+	return h
+}
+
+// BlobSidecars fetches the blobs of the block at the slot. Beacon nodes
+// only keep blobs for a few weeks.
+func (b *BeaconAPI) BlobSidecars(slot uint64) ([]BlobSidecar, error) {
+	var resp struct {
+		Data []struct {
+			Index      beaconUint    `json:"index"`
+			Blob       hexutil.Bytes `json:"blob"`
+			Commitment hexutil.Bytes `json:"kzg_commitment"`
+			Proof      hexutil.Bytes `json:"kzg_proof"`
+		} `json:"data"`
+	}
+	if err := b.get(fmt.Sprintf("/eth/v1/beacon/blob_sidecars/%d", slot), &resp); err != nil {
+		return nil, err
+	}
+	sidecars := make([]BlobSidecar, len(resp.Data))
+	for i, d := range resp.Data {
+		sidecars[i] = BlobSidecar{Index: uint64(d.Index), Blob: d.Blob, Commitment: d.Commitment, Proof: d.Proof}
+	}
+	return sidecars, nil
+}
@@ -3,22 +3,22 @@ package util
 import (
 	"context"
 	"fmt"
-	"log"
 	"sync"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 type Broker struct {
 	sync.RWMutex
-	client     *ethclient.Client
+	client     *Client
 	blockSubs  []chan<- *types.Block
 	txSubs     []chan<- *types.Transaction
 	headerSubs []chan<- *types.Header
 }
 
-func NewBroker(client *ethclient.Client) *Broker {
+func NewBroker(client *Client) *Broker {
 	return &Broker{client: client}
 }
 
@@ -49,58 +49,112 @@ func (b *Broker) SubscribeTransactions() chan *types.Transaction {
 	return ch
 }
 
-func (b *Broker) pubHeader(header *types.Header) {
+// the pub functions give up once the context is done, as subscribers stop
+// reading when the connection is closed
+
+func (b *Broker) pubHeader(ctx context.Context, header *types.Header) {
 	b.RLock()
 	defer b.RUnlock()
 	for _, s := range b.headerSubs {
-		s <- header
+		select {
+		case s <- header:
+		case <-ctx.Done():
+			return
+		}
 	}
 }
 
-func (b *Broker) pubBlock(block *types.Block) {
+func (b *Broker) pubBlock(ctx context.Context, block *types.Block) {
 	b.RLock()
 	defer b.RUnlock()
 	for _, s := range b.blockSubs {
-		s <- block
+		select {
+		case s <- block:
+		case <-ctx.Done():
+			return
+		}
 	}
 }
 
-func (b *Broker) pubTxn(t *types.Transaction) {
+func (b *Broker) pubTxn(ctx context.Context, t *types.Transaction) {
 	b.RLock()
 	defer b.RUnlock()
 	for _, s := range b.txSubs {
-		s <- t
+		select {
+		case s <- t:
+		case <-ctx.Done():
+			return
+		}
 	}
 }
 
-func (b *Broker) ListenForBlocks(ctx context.Context) {
+// backoff bounds the wait before resubscribing after the subscription
+// dropped, doubling from the first to the last
+const (
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+)
 
+// ListenForBlocks publishes new blocks until the context is done, erroring
+// if the node does not support subscriptions. Errors after subscribing are
+// passed to onErr, resubscribing with a backoff when the subscription
+// dropped.
+func (b *Broker) ListenForBlocks(ctx context.Context, onErr func(error)) error {
 	hChan := make(chan *types.Header)
 
 	sub, err := b.client.SubscribeNewHead(ctx, hChan)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
+	defer func() { sub.Unsubscribe() }()
 
+	backoff := minBackoff
 	for {
 		select {
+		case <-ctx.Done():
+			return nil
 		case err := <-sub.Err():
-			fmt.Println(err)
-			b.ListenForBlocks(ctx)
-			return
+			onErr(fmt.Errorf("block subscription dropped: %w", err))
+			sub.Unsubscribe()
+			next, err := b.resubscribe(ctx, hChan, &backoff, onErr)
+			if err != nil {
+				return nil
+			}
+			sub = next
 		case h := <-hChan:
-			b.pubHeader(h)
+			backoff = minBackoff
+			b.pubHeader(ctx, h)
 			block, err := b.client.BlockByHash(ctx, h.Hash())
 			if err != nil {
-				fmt.Println("failed to get block:", err)
+				onErr(fmt.Errorf("failed to get block %s: %w", h.Number, err))
 				continue
 			}
 
-			b.pubBlock(block)
+			b.pubBlock(ctx, block)
 			for _, t := range block.Transactions() {
-				b.pubTxn(t)
+				b.pubTxn(ctx, t)
 			}
 
 		}
 	}
 }
+
+// resubscribe waits out the backoff and subscribes again until it succeeds,
+// erroring only once the context is done
+func (b *Broker) resubscribe(ctx context.Context, hChan chan *types.Header, backoff *time.Duration, onErr func(error)) (ethereum.Subscription, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(*backoff):
+		}
+		if *backoff *= 2; *backoff > maxBackoff {
+			*backoff = maxBackoff
+		}
+		sub, err := b.client.SubscribeNewHead(ctx, hChan)
+		if err == nil {
+			return sub, nil
+		}
+		onErr(fmt.Errorf("failed to resubscribe to new blocks: %w", err))
+	}
+}
@@ -0,0 +1,443 @@
+package util
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// OpCode is a single EVM opcode.
+type OpCode byte
+
+const (
+	PUSH1    OpCode = 0x60
+	PUSH4    OpCode = 0x63
+	PUSH32   OpCode = 0x7f
+	DUP1     OpCode = 0x80
+	DUP16    OpCode = 0x8f
+	EQ       OpCode = 0x14
+	XOR      OpCode = 0x18
+	JUMPDEST OpCode = 0x5b
+)
+
+// opcode names up to the cancun fork
+var opcodeNames = map[OpCode]string{
+	0x00: "STOP",
+	0x01: "ADD",
+	0x02: "MUL",
+	0x03: "SUB",
+	0x04: "DIV",
+	0x05: "SDIV",
+	0x06: "MOD",
+	0x07: "SMOD",
+	0x08: "ADDMOD",
+	0x09: "MULMOD",
+	0x0a: "EXP",
+	0x0b: "SIGNEXTEND",
+	0x10: "LT",
+	0x11: "GT",
+	0x12: "SLT",
+	0x13: "SGT",
+	0x14: "EQ",
+	0x15: "ISZERO",
+	0x16: "AND",
+	0x17: "OR",
+	0x18: "XOR",
+	0x19: "NOT",
+	0x1a: "BYTE",
+	0x1b: "SHL",
+	0x1c: "SHR",
+	0x1d: "SAR",
+	0x20: "KECCAK256",
+	0x30: "ADDRESS",
+	0x31: "BALANCE",
+	0x32: "ORIGIN",
+	0x33: "CALLER",
+	0x34: "CALLVALUE",
+	0x35: "CALLDATALOAD",
+	0x36: "CALLDATASIZE",
+	0x37: "CALLDATACOPY",
+	0x38: "CODESIZE",
+	0x39: "CODECOPY",
+	0x3a: "GASPRICE",
+	0x3b: "EXTCODESIZE",
+	0x3c: "EXTCODECOPY",
+	0x3d: "RETURNDATASIZE",
+	0x3e: "RETURNDATACOPY",
+	0x3f: "EXTCODEHASH",
+	0x40: "BLOCKHASH",
+	0x41: "COINBASE",
+	0x42: "TIMESTAMP",
+	0x43: "NUMBER",
+	0x44: "PREVRANDAO",
+	0x45: "GASLIMIT",
+	0x46: "CHAINID",
+	0x47: "SELFBALANCE",
+	0x48: "BASEFEE",
+	0x49: "BLOBHASH",
+	0x4a: "BLOBBASEFEE",
+	0x50: "POP",
+	0x51: "MLOAD",
+	0x52: "MSTORE",
+	0x53: "MSTORE8",
+	0x54: "SLOAD",
+	0x55: "SSTORE",
+	0x56: "JUMP",
+	0x57: "JUMPI",
+	0x58: "PC",
+	0x59: "MSIZE",
+	0x5a: "GAS",
+	0x5b: "JUMPDEST",
+	0x5c: "TLOAD",
+	0x5d: "TSTORE",
+	0x5e: "MCOPY",
+	0x5f: "PUSH0",
+	0x60: "PUSH1",
+	0x61: "PUSH2",
+	0x62: "PUSH3",
+	0x63: "PUSH4",
+	0x64: "PUSH5",
+	0x65: "PUSH6",
+	0x66: "PUSH7",
+	0x67: "PUSH8",
+	0x68: "PUSH9",
+	0x69: "PUSH10",
+	0x6a: "PUSH11",
+	0x6b: "PUSH12",
+	0x6c: "PUSH13",
+	0x6d: "PUSH14",
+	0x6e: "PUSH15",
+	0x6f: "PUSH16",
+	0x70: "PUSH17",
+	0x71: "PUSH18",
+	0x72: "PUSH19",
+	0x73: "PUSH20",
+	0x74: "PUSH21",
+	0x75: "PUSH22",
+	0x76: "PUSH23",
+	0x77: "PUSH24",
+	0x78: "PUSH25",
+	0x79: "PUSH26",
+	0x7a: "PUSH27",
+	0x7b: "PUSH28",
+	0x7c: "PUSH29",
+	0x7d: "PUSH30",
+	0x7e: "PUSH31",
+	0x7f: "PUSH32",
+	0x80: "DUP1",
+	0x81: "DUP2",
+	0x82: "DUP3",
+	0x83: "DUP4",
+	0x84: "DUP5",
+	0x85: "DUP6",
+	0x86: "DUP7",
+	0x87: "DUP8",
+	0x88: "DUP9",
+	0x89: "DUP10",
+	0x8a: "DUP11",
+	0x8b: "DUP12",
+	0x8c: "DUP13",
+	0x8d: "DUP14",
+	0x8e: "DUP15",
+	0x8f: "DUP16",
+	0x90: "SWAP1",
+	0x91: "SWAP2",
+	0x92: "SWAP3",
+	0x93: "SWAP4",
+	0x94: "SWAP5",
+	0x95: "SWAP6",
+	0x96: "SWAP7",
+	0x97: "SWAP8",
+	0x98: "SWAP9",
+	0x99: "SWAP10",
+	0x9a: "SWAP11",
+	0x9b: "SWAP12",
+	0x9c: "SWAP13",
+	0x9d: "SWAP14",
+	0x9e: "SWAP15",
+	0x9f: "SWAP16",
+	0xa0: "LOG0",
+	0xa1: "LOG1",
+	0xa2: "LOG2",
+	0xa3: "LOG3",
+	0xa4: "LOG4",
+	0xf0: "CREATE",
+	0xf1: "CALL",
+	0xf2: "CALLCODE",
+	0xf3: "RETURN",
+	0xf4: "DELEGATECALL",
+	0xf5: "CREATE2",
+	0xfa: "STATICCALL",
+	0xfd: "REVERT",
+	0xfe: "INVALID",
+	0xff: "SELFDESTRUCT",
+}
+
+func (op OpCode) IsPush() bool {
+	return op >= PUSH1 && op <= PUSH32
+}
+
+func (op OpCode) String() string {
+	if name, ok := opcodeNames[op]; ok {
+		return name
+	}
+	return fmt.Sprintf("INVALID(0x%02x)", byte(op))
+}
+
+// Instruction is a single disassembled opcode.
+type Instruction struct {
+	PC  uint64
+	Op  OpCode
+	Arg []byte
+}
+
+func (i Instruction) String() string {
+	if len(i.Arg) == 0 {
+		return i.Op.String()
+	}
+	return fmt.Sprintf("%s %s", i.Op.String(), hexutil.Encode(i.Arg))
+}
+
+// Disassemble splits bytecode into instructions. Push data running past the
+// end of the code is truncated rather than treated as an error.
+func Disassemble(code []byte) []Instruction {
+	var instrs []Instruction
+	for pc := uint64(0); pc < uint64(len(code)); pc++ {
+		op := OpCode(code[pc])
+		instr := Instruction{PC: pc, Op: op}
+		if op.IsPush() {
+			size := uint64(op - PUSH1 + 1)
+			end := pc + 1 + size
+			if end > uint64(len(code)) {
+				end = uint64(len(code))
+			}
+			instr.Arg = code[pc+1 : end]
+			pc += size
+		}
+		instrs = append(instrs, instr)
+	}
+	return instrs
+}
+
+// FunctionSelectors extracts the 4 byte selectors compared against in a
+// contract's dispatcher, i.e. PUSH4 values that are checked for equality.
+func FunctionSelectors(instrs []Instruction) []string {
+	seen := make(map[string]bool)
+	var selectors []string
+	for i, instr := range instrs {
+		if instr.Op != PUSH4 || len(instr.Arg) != 4 {
+			continue
+		}
+		if !isSelectorCheck(instrs[i+1:]) {
+			continue
+		}
+		sel := hexutil.Encode(instr.Arg)
+		if sel == "0xffffffff" || seen[sel] {
+			continue
+		}
+		seen[sel] = true
+		selectors = append(selectors, sel)
+	}
+	return selectors
+}
+
+// isSelectorCheck reports whether the instructions following a PUSH4
+// compare it against the calldata selector, allowing for a DUP in between.
+func isSelectorCheck(next []Instruction) bool {
+	for i := 0; i < len(next) && i < 2; i++ {
+		switch op := next[i].Op; {
+		case op == EQ || op == XOR:
+			return true
+		case op >= DUP1 && op <= DUP16:
+			continue
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// Metadata is the solidity metadata appended to contract bytecode.
+type Metadata struct {
+	Solc         string
+	IPFS         string
+	Bzzr0        string
+	Bzzr1        string
+	Experimental bool
+	// Length is the size of the trailer including the 2 byte length suffix
+	Length int
+}
+
+// ParseMetadata decodes the CBOR encoded metadata trailer emitted by the
+// solidity compiler at the end of the runtime bytecode.
+func ParseMetadata(code []byte) (*Metadata, error) {
+	if len(code) < 2 {
+		return nil, errors.New("code too short")
+	}
+	size := int(binary.BigEndian.Uint16(code[len(code)-2:]))
+	if size == 0 || size+2 > len(code) {
+		return nil, errors.New("no metadata trailer")
+	}
+	data := code[len(code)-2-size : len(code)-2]
+
+	d := &cborDecoder{data: data}
+	fields, err := d.decodeMap()
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &Metadata{Length: size + 2}
+	for k, v := range fields {
+		switch k {
+		case "solc":
+			switch solc := v.(type) {
+			case []byte:
+				if len(solc) == 3 {
+					meta.Solc = fmt.Sprintf("%d.%d.%d", solc[0], solc[1], solc[2])
+				}
+			case string:
+				meta.Solc = solc
+			}
+		case "ipfs":
+			if b, ok := v.([]byte); ok {
+				meta.IPFS = base58Encode(b)
+			}
+		case "bzzr0":
+			if b, ok := v.([]byte); ok {
+				meta.Bzzr0 = hexutil.Encode(b)
+			}
+		case "bzzr1":
+			if b, ok := v.([]byte); ok {
+				meta.Bzzr1 = hexutil.Encode(b)
+			}
+		case "experimental":
+			if b, ok := v.(bool); ok {
+				meta.Experimental = b
+			}
+		}
+	}
+	return meta, nil
+}
+
+// cborDecoder supports the subset of CBOR used by solidity metadata: a
+// single map with text keys and byte string, text or bool values.
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) header() (major byte, arg uint64, err error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, errors.New("unexpected end of cbor data")
+	}
+	b := d.data[d.pos]
+	d.pos++
+	major, info := b>>5, b&0x1f
+
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info == 24:
+		if d.pos+1 > len(d.data) {
+			return 0, 0, errors.New("unexpected end of cbor data")
+		}
+		arg = uint64(d.data[d.pos])
+		d.pos++
+	case info == 25:
+		if d.pos+2 > len(d.data) {
+			return 0, 0, errors.New("unexpected end of cbor data")
+		}
+		arg = uint64(binary.BigEndian.Uint16(d.data[d.pos:]))
+		d.pos += 2
+	default:
+		return 0, 0, fmt.Errorf("unsupported cbor header: 0x%x", b)
+	}
+	return major, arg, nil
+}
+
+func (d *cborDecoder) value() (interface{}, error) {
+	major, arg, err := d.header()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case 0:
+		return arg, nil
+	case 2, 3:
+		if d.pos+int(arg) > len(d.data) {
+			return nil, errors.New("unexpected end of cbor data")
+		}
+		b := d.data[d.pos : d.pos+int(arg)]
+		d.pos += int(arg)
+		if major == 3 {
+			return string(b), nil
+		}
+		return b, nil
+	case 7:
+		switch arg {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported cbor type %d", major)
+}
+
+func (d *cborDecoder) decodeMap() (map[string]interface{}, error) {
+	major, n, err := d.header()
+	if err != nil {
+		return nil, err
+	}
+	if major != 5 {
+		return nil, errors.New("metadata is not a cbor map")
+	}
+	fields := make(map[string]interface{}, n)
+	for i := uint64(0); i < n; i++ {
+		k, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, errors.New("metadata key is not a string")
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = v
+	}
+	if d.pos != len(d.data) {
+		return nil, errors.New("trailing cbor data")
+	}
+	return fields, nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Encode encodes b with the bitcoin alphabet used by ipfs CIDs
+func base58Encode(b []byte) string {
+	num := new(big.Int).SetBytes(b)
+	base := big.NewInt(58)
+	mod := new(big.Int)
+
+	var out []byte
+	for num.Sign() > 0 {
+		num.DivMod(num, base, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
@@ -0,0 +1,90 @@
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestDisassemble(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want []string
+	}{
+		{name: "empty", code: "0x"},
+		{name: "pushes", code: "0x608060405200", want: []string{"0: PUSH1 0x80", "2: PUSH1 0x40", "4: MSTORE", "5: STOP"}},
+		{name: "invalid opcode", code: "0x0c5b", want: []string{"0: INVALID(0x0c)", "1: JUMPDEST"}},
+		{name: "truncated push", code: "0x0161ff", want: []string{"0: ADD", "1: PUSH2 0xff"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, instr := range Disassemble(hexutil.MustDecode(tt.code)) {
+				got = append(got, fmt.Sprintf("%d: %s", instr.PC, instr))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Disassemble(%s) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFunctionSelectors(t *testing.T) {
+	// a solidity dispatcher: the selector is compared directly, after a
+	// DUP, and repeated, while the mask pushed for an AND is skipped
+	code := "0x" +
+		"60003560e01c" + // PUSH1 0 CALLDATALOAD PUSH1 0xe0 SHR
+		"8063a9059cbb14610030" + "57" + // DUP1 PUSH4 EQ PUSH2 JUMPI
+		"6370a082318114610040" + "57" + // PUSH4 DUP2 EQ PUSH2 JUMPI
+		"8063a9059cbb14610030" + "57" + // repeated
+		"63ffffffff16" + // PUSH4 0xffffffff AND
+		"6312345678" + "50" // PUSH4 POP
+	want := []string{"0xa9059cbb", "0x70a08231"}
+	if got := FunctionSelectors(Disassemble(hexutil.MustDecode(code))); !reflect.DeepEqual(got, want) {
+		t.Errorf("FunctionSelectors = %q, want %q", got, want)
+	}
+}
+
+func TestParseMetadata(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want *Metadata
+	}{
+		{
+			name: "ipfs and solc version",
+			code: "0x6080" + "a26469706673582212204a1c5b7c3b0f2e8d9c6b5a4f3e2d1c0b0a99887766554433221100ffeeddccbb64736f6c6343000813" + "0033",
+			want: &Metadata{Solc: "0.8.19", IPFS: "QmTKwxYsxGCPmYBL68CUNnCcKr4Sx2LeA9YsmEHmx4QZAA", Length: 53},
+		},
+		{
+			name: "swarm",
+			code: "0x00" + "a165627a7a723058204a1c5b7c3b0f2e8d9c6b5a4f3e2d1c0b0a99887766554433221100ffeeddccbb" + "0029",
+			want: &Metadata{Bzzr0: "0x4a1c5b7c3b0f2e8d9c6b5a4f3e2d1c0b0a99887766554433221100ffeeddccbb", Length: 43},
+		},
+		{name: "too short", code: "0x00"},
+		{name: "length past the code", code: "0x6080ff00"},
+		{name: "not cbor", code: "0x60806040" + "0002"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMetadata(hexutil.MustDecode(tt.code))
+			if tt.want == nil {
+				if err == nil {
+					t.Fatalf("ParseMetadata = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMetadata: %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("ParseMetadata = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var blockTags = []string{"latest", "pending", "earliest", "safe", "finalized"}
+
+// ParseBlockTag converts user input into a block parameter accepted by
+// the json-rpc api. Empty input defaults to latest.
+func ParseBlockTag(input string) (string, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "" {
+		return "latest", nil
+	}
+	for _, tag := range blockTags {
+		if input == tag {
+			return tag, nil
+		}
+	}
+	num, ok := parseBigInt(input)
+	if !ok || num.Sign() < 0 {
+		return "", fmt.Errorf("invalid block: %s", input)
+	}
+	return hexutil.EncodeBig(num), nil
+}
+
+// CallMethod executes an eth_call of a contract method at the given block
+// and returns the decoded outputs.
+func CallMethod(ctx context.Context, client *rpc.Client, to common.Address, method *abi.Method, args []interface{}, block string) ([]interface{}, error) {
+	input, err := method.Inputs.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	data := append(append([]byte{}, method.ID...), input...)
+
+	callArg := map[string]interface{}{
+		"to":   to,
+		"data": hexutil.Bytes(data),
+	}
+
+	var result hexutil.Bytes
+	err = client.CallContext(ctx, &result, "eth_call", callArg, block)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 && len(method.Outputs) > 0 {
+		return nil, fmt.Errorf("execution returned no data")
+	}
+	return method.Outputs.Unpack(result)
+}
+
+// ParseArgs parses the textual inputs of a method into values that can be
+// packed for the method's input arguments.
+func ParseArgs(args abi.Arguments, inputs []string) ([]interface{}, error) {
+	if len(args) != len(inputs) {
+		return nil, fmt.Errorf("expected %d arguments, got %d", len(args), len(inputs))
+	}
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		v, err := ParseArg(arg.Type, inputs[i])
+		if err != nil {
+			name := arg.Name
+			if name == "" {
+				name = fmt.Sprintf("[%d]", i)
+			}
+			return nil, fmt.Errorf("%s (%s): %w", name, arg.Type, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// ParseArg parses a single textual input into the go value expected by the
+// abi encoder for the given type. Arrays and tuples are written as
+// bracketed, comma separated lists, e.g. [1,2,3] or [0xabc...,[1,2]].
+func ParseArg(t abi.Type, input string) (interface{}, error) {
+	v, err := parseValue(t, input)
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+func parseValue(t abi.Type, input string) (reflect.Value, error) {
+	input = unquote(strings.TrimSpace(input))
+	typ := t.GetType()
+
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		return parseInt(t, input)
+
+	case abi.BoolTy:
+		b, err := strconv.ParseBool(input)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid bool: %s", input)
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.StringTy:
+		return reflect.ValueOf(input), nil
+
+	case abi.AddressTy:
+		if !common.IsHexAddress(input) {
+			return reflect.Value{}, fmt.Errorf("invalid address: %s", input)
+		}
+		return reflect.ValueOf(common.HexToAddress(input)), nil
+
+	case abi.BytesTy:
+		b, err := hexutil.Decode(input)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid bytes: %w", err)
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.FixedBytesTy:
+		b, err := hexutil.Decode(input)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid bytes%d: %w", t.Size, err)
+		}
+		if len(b) != t.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d bytes, got %d", t.Size, len(b))
+		}
+		v := reflect.New(typ).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v, nil
+
+	case abi.SliceTy, abi.ArrayTy:
+		items, err := splitList(input)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		var v reflect.Value
+		if t.T == abi.ArrayTy {
+			if len(items) != t.Size {
+				return reflect.Value{}, fmt.Errorf("expected %d elements, got %d", t.Size, len(items))
+			}
+			v = reflect.New(typ).Elem()
+		} else {
+			v = reflect.MakeSlice(typ, len(items), len(items))
+		}
+		for i, item := range items {
+			ev, err := parseValue(*t.Elem, item)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %w", i, err)
+			}
+			v.Index(i).Set(ev)
+		}
+		return v, nil
+
+	case abi.TupleTy:
+		items, err := splitList(input)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(items) != len(t.TupleElems) {
+			return reflect.Value{}, fmt.Errorf("expected %d fields, got %d", len(t.TupleElems), len(items))
+		}
+		v := reflect.New(typ).Elem()
+		for i, item := range items {
+			fv, err := parseValue(*t.TupleElems[i], item)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("field %s: %w", t.TupleRawNames[i], err)
+			}
+			v.Field(i).Set(fv)
+		}
+		return v, nil
+
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type: %s", t)
+	}
+}
+
+func parseInt(t abi.Type, input string) (reflect.Value, error) {
+	num, ok := parseBigInt(input)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("invalid integer: %s", input)
+	}
+
+	if t.T == abi.UintTy {
+		if num.Sign() < 0 {
+			return reflect.Value{}, fmt.Errorf("negative value for %s", t)
+		}
+		if num.BitLen() > t.Size {
+			return reflect.Value{}, fmt.Errorf("value overflows %s", t)
+		}
+	} else {
+		max := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+		min := new(big.Int).Neg(max)
+		if num.Cmp(min) < 0 || num.Cmp(max) >= 0 {
+			return reflect.Value{}, fmt.Errorf("value overflows %s", t)
+		}
+	}
+
+	typ := t.GetType()
+	if typ == reflect.TypeOf(&big.Int{}) {
+		return reflect.ValueOf(num), nil
+	}
+	v := reflect.New(typ).Elem()
+	if t.T == abi.UintTy {
+		v.SetUint(num.Uint64())
+	} else {
+		v.SetInt(num.Int64())
+	}
+	return v, nil
+}
+
+func parseBigInt(input string) (*big.Int, bool) {
+	input = strings.ReplaceAll(input, "_", "")
+	if strings.HasPrefix(input, "0x") || strings.HasPrefix(input, "0X") {
+		return new(big.Int).SetString(input[2:], 16)
+	}
+	return new(big.Int).SetString(input, 10)
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '\'' && s[len(s)-1] == '\'') {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// splitList splits a bracketed list into its top level elements,
+// respecting nested lists and quoted strings.
+func splitList(input string) ([]string, error) {
+	if len(input) < 2 {
+		return nil, fmt.Errorf("expected a list, got: %s", input)
+	}
+	open, close := input[0], input[len(input)-1]
+	if !(open == '[' && close == ']') && !(open == '(' && close == ')') {
+		return nil, fmt.Errorf("expected a list, got: %s", input)
+	}
+	inner := strings.TrimSpace(input[1 : len(input)-1])
+	if inner == "" {
+		return []string{}, nil
+	}
+
+	var (
+		items []string
+		depth int
+		quote rune
+		start int
+	)
+	for i, r := range inner {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[' || r == '(':
+			depth++
+		case r == ']' || r == ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced brackets: %s", input)
+			}
+		case r == ',' && depth == 0:
+			items = append(items, strings.TrimSpace(inner[start:i]))
+			start = i + 1
+		}
+	}
+	if depth != 0 || quote != 0 {
+		return nil, fmt.Errorf("unbalanced list: %s", input)
+	}
+	items = append(items, strings.TrimSpace(inner[start:]))
+	return items, nil
+}
+
+// FormatValue renders a decoded abi value for display.
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	case *big.Int:
+		return v.String()
+	case string:
+		return strconv.Quote(v)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		return formatList(rv, "[", "]")
+	case reflect.Slice:
+		return formatList(rv, "[", "]")
+	case reflect.Struct:
+		fields := make([]string, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			fields[i] = fmt.Sprintf("%s: %s", rv.Type().Field(i).Name, FormatValue(rv.Field(i).Interface()))
+		}
+		return fmt.Sprintf("(%s)", strings.Join(fields, ", "))
+	}
+	return fmt.Sprint(value)
+}
+
+func formatList(rv reflect.Value, open, close string) string {
+	items := make([]string, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		items[i] = FormatValue(rv.Index(i).Interface())
+	}
+	return open + strings.Join(items, ", ") + close
+}
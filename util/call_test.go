@@ -0,0 +1,107 @@
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+func TestParseArg(t *testing.T) {
+	newType := func(typ string, components ...abi.ArgumentMarshaling) abi.Type {
+		t.Helper()
+		ty, err := abi.NewType(typ, "", components)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ty
+	}
+	pair := newType("tuple", abi.ArgumentMarshaling{Name: "amount", Type: "uint256"}, abi.ArgumentMarshaling{Name: "ok", Type: "bool"})
+
+	tests := []struct {
+		name  string
+		typ   abi.Type
+		input string
+		// want is the formatted value, empty when parsing fails
+		want string
+	}{
+		{name: "uint256 decimal", typ: newType("uint256"), input: "1_000", want: "1000"},
+		{name: "uint256 hex", typ: newType("uint256"), input: "0xff", want: "255"},
+		{name: "uint8", typ: newType("uint8"), input: "255", want: "255"},
+		{name: "uint8 overflow", typ: newType("uint8"), input: "256"},
+		{name: "uint negative", typ: newType("uint64"), input: "-1"},
+		{name: "int8 min", typ: newType("int8"), input: "-128", want: "-128"},
+		{name: "int8 underflow", typ: newType("int8"), input: "-129"},
+		{name: "bool", typ: newType("bool"), input: "true", want: "true"},
+		{name: "address", typ: newType("address"), input: " 0x000000000000000000000000000000000000dEaD ", want: "0x000000000000000000000000000000000000dEaD"},
+		{name: "invalid address", typ: newType("address"), input: "0xdead"},
+		{name: "quoted string", typ: newType("string"), input: `"a, b"`, want: "a, b"},
+		{name: "bytes4", typ: newType("bytes4"), input: "0x12345678", want: "[18 52 86 120]"},
+		{name: "bytes4 wrong size", typ: newType("bytes4"), input: "0x1234"},
+		{name: "slice", typ: newType("uint256[]"), input: "[1, 2,3]", want: "[1 2 3]"},
+		{name: "empty slice", typ: newType("uint256[]"), input: "[]", want: "[]"},
+		{name: "fixed array", typ: newType("uint8[2]"), input: "[1,2]", want: "[1 2]"},
+		{name: "fixed array length", typ: newType("uint8[2]"), input: "[1]"},
+		{name: "nested", typ: newType("uint8[][]"), input: "[[1,2],[],[3]]", want: "[[1 2] [] [3]]"},
+		{name: "strings with commas", typ: newType("string[]"), input: `["a,b", 'c]']`, want: "[a,b c]]"},
+		{name: "tuple", typ: pair, input: "(5, false)", want: "{5 false}"},
+		{name: "tuple fields", typ: pair, input: "(5)"},
+		{name: "not a list", typ: newType("uint256[]"), input: "1,2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseArg(tt.typ, tt.input)
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("ParseArg(%q) = %v, want an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseArg(%q): %v", tt.input, err)
+			}
+			if s := fmt.Sprint(got); s != tt.want {
+				t.Errorf("ParseArg(%q) = %s, want %s", tt.input, s, tt.want)
+			}
+			if reflect.TypeOf(got) != tt.typ.GetType() {
+				t.Errorf("ParseArg(%q) is a %T, want %s", tt.input, got, tt.typ.GetType())
+			}
+		})
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{input: "[]", want: []string{}},
+		{input: "[ 1 ]", want: []string{"1"}},
+		{input: "[1, [2, 3], (4, 5)]", want: []string{"1", "[2, 3]", "(4, 5)"}},
+		{input: `["a,b", 'c,d']`, want: []string{`"a,b"`, `'c,d'`}},
+		{input: "[1, 2"},
+		{input: "[[1, 2]"},
+		{input: `["a]`},
+		{input: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := splitList(tt.input)
+			if tt.want == nil {
+				if err == nil {
+					t.Fatalf("splitList(%q) = %q, want an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitList(%q): %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitList(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Chain is a profile of a network: where to reach it, where to look up
+// contracts and how to link to it.
+type Chain struct {
+	Name    string
+	RpcUrl  string
+	ChainID uint64
+	// Currency is the symbol of the native currency
+	Currency string
+	// explorer url templates, {} is replaced with the block number,
+	// transaction hash or address
+	BlockURL   string
+	TxURL      string
+	AddressURL string
+	// API is the etherscan compatible api used for ABIs and sources
+	API ContractAPI
+	// BeaconURL is an optional beacon node api for consensus data
+	BeaconURL string
+	// FeeMarket predicts the base fee, nil when the chain's parameters are
+	// unknown or it prices gas otherwise, as arbitrum does
+	FeeMarket *FeeMarket
+	// Labels maps lower case addresses to known names
+	Labels map[string]string
+}
+
+// ContractAPI is an etherscan compatible api for verified contracts.
+type ContractAPI struct {
+	URL string
+	Key string
+}
+
+func explorer(base string) (block, tx, address string) {
+	return base + "/block/{}", base + "/tx/{}", base + "/address/{}"
+}
+
+func newChain(name string, id uint64, currency, explorerURL, apiURL string) Chain {
+	c := Chain{Name: name, ChainID: id, Currency: currency, API: ContractAPI{URL: apiURL}}
+	c.BlockURL, c.TxURL, c.AddressURL = explorer(explorerURL)
+	return c
+}
+
+// DefaultChains returns the built in profiles, which have no rpc url
+func DefaultChains() map[string]Chain {
+	mainnet := newChain("mainnet", 1, "ETH", "https://etherscan.io", "https://api.etherscan.io/api")
+	mainnet.Labels = commonAddresses
+
+	chains := map[string]Chain{
+		"mainnet":  mainnet,
+		"goerli":   newChain("goerli", 5, "ETH", "https://goerli.etherscan.io", "https://api-goerli.etherscan.io/api"),
+		"sepolia":  newChain("sepolia", 11155111, "ETH", "https://sepolia.etherscan.io", "https://api-sepolia.etherscan.io/api"),
+		"optimism": newChain("optimism", 10, "ETH", "https://optimistic.etherscan.io", "https://api-optimistic.etherscan.io/api"),
+		"arbitrum": newChain("arbitrum", 42161, "ETH", "https://arbiscan.io", "https://api.arbiscan.io/api"),
+		"base":     newChain("base", 8453, "ETH", "https://basescan.org", "https://api.basescan.org/api"),
+		"polygon":  newChain("polygon", 137, "MATIC", "https://polygonscan.com", "https://api.polygonscan.com/api"),
+	}
+	markets := map[string]FeeMarket{
+		"mainnet":  MainnetFeeMarket,
+		"goerli":   MainnetFeeMarket,
+		"sepolia":  MainnetFeeMarket,
+		"optimism": OPStackFeeMarket,
+		"base":     OPStackFeeMarket,
+		"polygon":  PolygonFeeMarket,
+	}
+	for name, market := range markets {
+		market := market
+		chain := chains[name]
+		chain.FeeMarket = &market
+		chains[name] = chain
+	}
+	return chains
+}
+
+// Merge returns the chain with the set fields of the override applied.
+// Labels are merged.
+func (c Chain) Merge(o Chain) Chain {
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(&c.Name, o.Name)
+	set(&c.RpcUrl, o.RpcUrl)
+	set(&c.Currency, o.Currency)
+	set(&c.BlockURL, o.BlockURL)
+	set(&c.TxURL, o.TxURL)
+	set(&c.AddressURL, o.AddressURL)
+	set(&c.API.URL, o.API.URL)
+	set(&c.API.Key, o.API.Key)
+	set(&c.BeaconURL, o.BeaconURL)
+	if o.ChainID != 0 {
+		c.ChainID = o.ChainID
+	}
+	if o.FeeMarket != nil {
+		c.FeeMarket = o.FeeMarket
+	}
+
+	labels := make(map[string]string, len(c.Labels)+len(o.Labels))
+	for _, l := range []map[string]string{c.Labels, o.Labels} {
+		for addr, name := range l {
+			labels[strings.ToLower(addr)] = name
+		}
+	}
+	c.Labels = labels
+	return c
+}
+
+// String names the chain along with its id
+func (c *Chain) String() string {
+	if c.ChainID == 0 {
+		return c.Name
+	}
+	return fmt.Sprintf("%s (%d)", c.Name, c.ChainID)
+}
+
+// Symbol returns the currency symbol, ETH if none is configured
+func (c *Chain) Symbol() string {
+	if c.Currency == "" {
+		return "ETH"
+	}
+	return c.Currency
+}
+
+// ExplorerURL fills in a url template, erroring if the chain has no
+// explorer configured
+func (c *Chain) ExplorerURL(template, id string) (string, error) {
+	if template == "" {
+		return "", fmt.Errorf("no explorer configured for %s", c.Name)
+	}
+	return strings.ReplaceAll(template, "{}", id), nil
+}
+
+// Label returns the known name of an address
+func (c *Chain) Label(addr string) (string, bool) {
+	name, ok := c.Labels[strings.ToLower(addr)]
+	return name, ok
+}
+
+// ChainByID returns the profile with the chain id, checking the names in
+// order
+func ChainByID(chains map[string]Chain, id uint64) (Chain, bool) {
+	names := make([]string, 0, len(chains))
+	for name := range chains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if chains[name].ChainID == id {
+			return chains[name], true
+		}
+	}
+	return Chain{}, false
+}
+
+// SetExplorer fills in the url templates not already set for an explorer
+// with etherscan style paths
+func (c *Chain) SetExplorer(base string) {
+	base = strings.TrimSuffix(base, "/")
+	block, tx, address := explorer(base)
+	for _, f := range []struct {
+		dst *string
+		val string
+	}{{&c.BlockURL, block}, {&c.TxURL, tx}, {&c.AddressURL, address}} {
+		if *f.dst == "" {
+			*f.dst = f.val
+		}
+	}
+}
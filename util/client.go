@@ -0,0 +1,176 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Client is an ethclient that decodes the blocks and transactions of
+// rollups, standing in for the transaction types go-ethereum can't decode
+// and recording what they stand in for in its RollupTxs.
+type Client struct {
+	*ethclient.Client
+	*RollupTxs
+	rpc *rpc.Client
+}
+
+func NewClient(rpc *rpc.Client) *Client {
+	return &Client{Client: ethclient.NewClient(rpc), RollupTxs: NewRollupTxs(), rpc: rpc}
+}
+
+// decodeTx decodes a transaction, or a stand-in for those of an unknown type
+func (c *Client) decodeTx(raw json.RawMessage) (*types.Transaction, error) {
+	txn := new(types.Transaction)
+	err := txn.UnmarshalJSON(raw)
+	if !errors.Is(err, types.ErrTxTypeNotSupported) {
+		return txn, err
+	}
+	txn, r, err := decodeRollupTx(raw)
+	if err != nil {
+		return nil, err
+	}
+	c.RollupTxs.add(txn, r)
+	return txn, nil
+}
+
+func blockNumberArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	if number.Sign() >= 0 {
+		return hexutil.EncodeBig(number)
+	}
+	return rpc.BlockNumber(number.Int64()).String()
+}
+
+func (c *Client) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return c.getBlock(ctx, "eth_getBlockByHash", hash, true)
+}
+
+// BlockByNumber returns the block of the number, or the latest block when
+// nil. Negative numbers are tags such as rpc.SafeBlockNumber.
+func (c *Client) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return c.getBlock(ctx, "eth_getBlockByNumber", blockNumberArg(number), true)
+}
+
+type rpcBlock struct {
+	Hash         common.Hash         `json:"hash"`
+	Transactions []json.RawMessage   `json:"transactions"`
+	UncleHashes  []common.Hash       `json:"uncles"`
+	Withdrawals  []*types.Withdrawal `json:"withdrawals,omitempty"`
+}
+
+// getBlock follows ethclient, but decodes the transactions one by one
+func (c *Client) getBlock(ctx context.Context, method string, args ...interface{}) (*types.Block, error) {
+	var raw json.RawMessage
+	if err := c.rpc.CallContext(ctx, &raw, method, args...); err != nil {
+		return nil, err
+	}
+	var head *types.Header
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, ethereum.NotFound
+	}
+	var body rpcBlock
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, err
+	}
+
+	var uncles []*types.Header
+	if len(body.UncleHashes) > 0 {
+		uncles = make([]*types.Header, len(body.UncleHashes))
+		reqs := make([]rpc.BatchElem, len(body.UncleHashes))
+		for i := range reqs {
+			reqs[i] = rpc.BatchElem{
+				Method: "eth_getUncleByBlockHashAndIndex",
+				Args:   []interface{}{body.Hash, hexutil.EncodeUint64(uint64(i))},
+				Result: &uncles[i],
+			}
+		}
+		if err := c.rpc.BatchCallContext(ctx, reqs); err != nil {
+			return nil, err
+		}
+		for i := range reqs {
+			if reqs[i].Error != nil {
+				return nil, reqs[i].Error
+			}
+			if uncles[i] == nil {
+				return nil, fmt.Errorf("got null header for uncle %d of block %s", i, body.Hash.Hex())
+			}
+		}
+	}
+
+	txns := make([]*types.Transaction, len(body.Transactions))
+	for i, rawTxn := range body.Transactions {
+		txn, err := c.decodeTx(rawTxn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode transaction %d of block %s: %w", i, body.Hash.Hex(), err)
+		}
+		txns[i] = txn
+	}
+	return types.NewBlockWithHeader(head).WithBody(txns, uncles).WithWithdrawals(body.Withdrawals), nil
+}
+
+// TransactionByHash returns the transaction with the given hash, and
+// whether it is still pending.
+func (c *Client) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	var raw json.RawMessage
+	if err := c.rpc.CallContext(ctx, &raw, "eth_getTransactionByHash", hash); err != nil {
+		return nil, false, err
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, false, ethereum.NotFound
+	}
+	txn, err := c.decodeTx(raw)
+	if err != nil {
+		return nil, false, err
+	}
+	var extra struct {
+		BlockHash *common.Hash `json:"blockHash"`
+	}
+	if err := json.Unmarshal(raw, &extra); err != nil {
+		return nil, false, err
+	}
+	return txn, extra.BlockHash == nil, nil
+}
+
+// Receipt is a transaction receipt along with the layer 1 fields of
+// rollups, nil on other chains
+type Receipt struct {
+	*types.Receipt
+	L1 *L1Receipt
+}
+
+// Receipt returns the receipt of a transaction, decoding the layer 1
+// fields of rollups from the same response
+func (c *Client) Receipt(ctx context.Context, hash common.Hash) (*Receipt, error) {
+	var raw json.RawMessage
+	if err := c.rpc.CallContext(ctx, &raw, "eth_getTransactionReceipt", hash); err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, ethereum.NotFound
+	}
+	rec := &Receipt{Receipt: new(types.Receipt)}
+	if err := json.Unmarshal(raw, rec.Receipt); err != nil {
+		return nil, err
+	}
+	l1, err := decodeL1Receipt(raw)
+	if err != nil {
+		return nil, err
+	}
+	rec.L1 = l1
+	return rec, nil
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -9,6 +10,13 @@ import (
 	"github.com/go-resty/resty/v2"
 )
 
+// ErrRateLimited is returned when etherscan rejects a request for exceeding
+// the rate limit of the api key.
+var ErrRateLimited = errors.New("etherscan rate limit reached")
+
+// ErrNoContractAPI is returned when the chain has no contract api configured
+var ErrNoContractAPI = errors.New("no contract api configured for this chain")
+
 // taken from https://gist.github.com/crazygit/9279a3b26461d7cb03e807a6362ec855
 type rawABIResponse struct {
 	Status  *string `json:"status"`
@@ -55,7 +63,10 @@ type contractDataResponse struct {
 	// Result *
 }
 
-func GetContractRawABI(address string, apiKey string) (*rawABIResponse, error) {
+func GetContractRawABI(address string, api ContractAPI) (*rawABIResponse, error) {
+	if api.URL == "" {
+		return nil, ErrNoContractAPI
+	}
 	client := resty.New()
 	rawABIResponse := &rawABIResponse{}
 	resp, err := client.R().
@@ -63,10 +74,10 @@ func GetContractRawABI(address string, apiKey string) (*rawABIResponse, error) {
 			"module":  "contract",
 			"action":  "getabi",
 			"address": address,
-			"apikey":  apiKey,
+			"apikey":  api.Key,
 		}).
 		SetResult(rawABIResponse).
-		Get("https://api.etherscan.io/api")
+		Get(api.URL)
 
 	if err != nil {
 		return nil, err
@@ -74,6 +85,9 @@ func GetContractRawABI(address string, apiKey string) (*rawABIResponse, error) {
 	if !resp.IsSuccess() {
 		return nil, fmt.Errorf(fmt.Sprintf("Get contract raw abi failed: %s\n", resp))
 	}
+	if *rawABIResponse.Status != "1" && strings.Contains(strings.ToLower(*rawABIResponse.Result), "rate limit") {
+		return nil, ErrRateLimited
+	}
 	if *rawABIResponse.Status != "1" {
 		return nil, fmt.Errorf(fmt.Sprintf("Get contract raw abi failed: %s\n", *rawABIResponse.Result))
 	}
@@ -81,8 +95,8 @@ func GetContractRawABI(address string, apiKey string) (*rawABIResponse, error) {
 	return rawABIResponse, nil
 }
 
-func GetContractABI(contractAddress string, etherscanAPIKey string) (*abi.ABI, error) {
-	rawABIResponse, err := GetContractRawABI(contractAddress, etherscanAPIKey)
+func GetContractABI(contractAddress string, api ContractAPI) (*abi.ABI, error) {
+	rawABIResponse, err := GetContractRawABI(contractAddress, api)
 	if err != nil {
 		return nil, err
 	}
@@ -110,7 +124,10 @@ func DecodeTransactionInputData(contractABI *abi.ABI, data []byte) (mehtod strin
 	return method.Name, inputsMap, nil
 }
 
-func getRawContractData(address string, apiKey string) (*contractDataResponse, error) {
+func getRawContractData(address string, api ContractAPI) (*contractDataResponse, error) {
+	if api.URL == "" {
+		return nil, ErrNoContractAPI
+	}
 	client := resty.New()
 	rawResponse := &contractDataResponse{}
 	resp, err := client.R().
@@ -118,10 +135,10 @@ func getRawContractData(address string, apiKey string) (*contractDataResponse, e
 			"module":  "contract",
 			"action":  "getsourcecode",
 			"address": address,
-			"apikey":  apiKey,
+			"apikey":  api.Key,
 		}).
 		SetResult(rawResponse).
-		Get("https://api.etherscan.io/api")
+		Get(api.URL)
 
 	if err != nil {
 		return nil, err
@@ -136,8 +153,8 @@ func getRawContractData(address string, apiKey string) (*contractDataResponse, e
 	return rawResponse, nil
 }
 
-func GetContractData(address, apiKey string) (*ContractData, error) {
-	contractResp, err := getRawContractData(address, apiKey)
+func GetContractData(address string, api ContractAPI) (*ContractData, error) {
+	contractResp, err := getRawContractData(address, api)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,90 @@
+package util
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// StructLog is a single step of geth's struct logger.
+type StructLog struct {
+	PC      uint64            `json:"pc"`
+	Op      string            `json:"op"`
+	Gas     uint64            `json:"gas"`
+	GasCost uint64            `json:"gasCost"`
+	Depth   int               `json:"depth"`
+	Error   string            `json:"error,omitempty"`
+	Stack   []string          `json:"stack"`
+	Memory  []string          `json:"memory"`
+	Storage map[string]string `json:"storage"`
+
+	// CodeAddress is the address of the code executing this step, nil
+	// while running init code of a contract creation
+	CodeAddress *common.Address `json:"-"`
+}
+
+// IsCall reports whether the step calls into or creates another contract.
+func (l StructLog) IsCall() bool {
+	switch l.Op {
+	case "CALL", "CALLCODE", "DELEGATECALL", "STATICCALL", "CREATE", "CREATE2":
+		return true
+	}
+	return false
+}
+
+// StepTrace is the full opcode level trace of a transaction.
+type StepTrace struct {
+	Gas         uint64      `json:"gas"`
+	Failed      bool        `json:"failed"`
+	ReturnValue string      `json:"returnValue"`
+	StructLogs  []StructLog `json:"structLogs"`
+}
+
+// TraceTransactionSteps re-executes a transaction with the struct logger,
+// returning every executed opcode with its stack, memory and storage.
+func TraceTransactionSteps(ctx context.Context, client *rpc.Client, hash common.Hash, to *common.Address) (*StepTrace, error) {
+	config := map[string]interface{}{
+		"enableMemory":     true,
+		"disableStorage":   false,
+		"enableReturnData": true,
+	}
+
+	trace := &StepTrace{}
+	err := client.CallContext(ctx, trace, "debug_traceTransaction", hash, config)
+	if err != nil {
+		return nil, err
+	}
+	trace.resolveCodeAddresses(to)
+	return trace, nil
+}
+
+// resolveCodeAddresses tracks the call stack through the trace to record
+// which contract's code runs at each step.
+func (t *StepTrace) resolveCodeAddresses(to *common.Address) {
+	stack := []*common.Address{to}
+	for i := range t.StructLogs {
+		l := &t.StructLogs[i]
+		for len(stack) > l.Depth && len(stack) > 1 {
+			stack = stack[:len(stack)-1]
+		}
+		for len(stack) < l.Depth {
+			stack = append(stack, nil)
+		}
+		l.CodeAddress = stack[len(stack)-1]
+
+		if !l.IsCall() || i+1 >= len(t.StructLogs) || t.StructLogs[i+1].Depth <= l.Depth {
+			continue
+		}
+		var target *common.Address
+		if strings.HasPrefix(l.Op, "CALL") || l.Op == "DELEGATECALL" || l.Op == "STATICCALL" {
+			// the callee is the second item from the top of the stack
+			if len(l.Stack) >= 2 {
+				addr := common.HexToAddress(l.Stack[len(l.Stack)-2])
+				target = &addr
+			}
+		}
+		stack = append(stack, target)
+	}
+}
@@ -0,0 +1,249 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+var (
+	ErrENSDisabled = errors.New("ENS is disabled")
+	ErrNoENS       = errors.New("the chain has no ENS registry")
+)
+
+// ensTTL is how long reverse lookups are cached, including addresses
+// without a name. Failed lookups aren't cached.
+const ensTTL = 15 * time.Minute
+
+// maxENSNames is the number of reverse lookups kept before the cache is
+// reset
+const maxENSNames = 10000
+
+// ENSTextKeys are the text records looked up of a name
+var ENSTextKeys = []string{"avatar", "display", "description", "url", "email", "location",
+	"notice", "keywords", "com.twitter", "com.github", "com.discord", "org.telegram"}
+
+type ensName struct {
+	name    string
+	expires time.Time
+}
+
+// ENS resolves names on the chain's ENS registry. Reverse lookups are only
+// trusted when the name resolves back to the address, and are cached.
+type ENS struct {
+	client   *Client
+	disabled bool
+
+	mu sync.Mutex
+	// checked is set once the registry was looked for, available when it
+	// was found
+	checked   bool
+	available bool
+	names     map[common.Address]ensName
+}
+
+func NewENS(client *Client, disabled bool) *ENS {
+	return &ENS{
+		client:   client,
+		disabled: disabled,
+		names:    make(map[common.Address]ensName),
+	}
+}
+
+// Enabled reports whether ENS lookups may be made, i.e. they aren't
+// disabled in the config
+func (e *ENS) Enabled() bool {
+	return !e.disabled
+}
+
+// check returns an error when ENS is disabled or the chain has no registry
+func (e *ENS) check(ctx context.Context) error {
+	if e.disabled {
+		return ErrENSDisabled
+	}
+	e.mu.Lock()
+	checked, available := e.checked, e.available
+	e.mu.Unlock()
+	if !checked {
+		registry, err := ens.RegistryContractAddress(e.client)
+		if err != nil {
+			return err
+		}
+		code, err := e.client.CodeAt(ctx, registry, nil)
+		if err != nil {
+			return err
+		}
+		available = len(code) > 0
+		e.mu.Lock()
+		e.checked, e.available = true, available
+		e.mu.Unlock()
+	}
+	if !available {
+		return ErrNoENS
+	}
+	return nil
+}
+
+// Name returns the primary name of an address, or an empty name when it
+// has none or the name doesn't resolve back to the address. Errors are
+// returned for failed calls, which are retried on the next lookup.
+func (e *ENS) Name(ctx context.Context, addr common.Address) (string, error) {
+	if err := e.check(ctx); err != nil {
+		return "", err
+	}
+	e.mu.Lock()
+	cached, ok := e.names[addr]
+	e.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.name, nil
+	}
+
+	name, err := e.reverse(ctx, addr)
+	if err != nil {
+		return "", err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.names) >= maxENSNames {
+		e.names = make(map[common.Address]ensName)
+	}
+	e.names[addr] = ensName{name: name, expires: time.Now().Add(ensTTL)}
+	return name, nil
+}
+
+// reverse looks up the name of an address and checks that it resolves
+// back to the address, erroring only when a call failed
+func (e *ENS) reverse(ctx context.Context, addr common.Address) (string, error) {
+	registry, err := ens.NewRegistry(e.client)
+	if err != nil {
+		return "", err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+
+	node, err := ens.NameHash(fmt.Sprintf("%x.addr.reverse", addr.Bytes()))
+	if err != nil {
+		return "", err
+	}
+	reverse, err := e.resolverOf(opts, registry, node)
+	if reverse == nil {
+		return "", err
+	}
+	name, err := reverse.Name(opts, node)
+	if errors.Is(err, bind.ErrNoCode) {
+		return "", nil
+	}
+	if err != nil || name == "" {
+		return "", err
+	}
+
+	if node, err = ens.NameHash(name); err != nil {
+		return "", nil
+	}
+	forward, err := e.resolverOf(opts, registry, node)
+	if forward == nil {
+		return "", err
+	}
+	resolved, err := forward.Addr(opts, node)
+	if errors.Is(err, bind.ErrNoCode) {
+		return "", nil
+	}
+	if err != nil || resolved != addr {
+		return "", err
+	}
+	return name, nil
+}
+
+// resolverOf returns the resolver of a node, nil when it has none
+func (e *ENS) resolverOf(opts *bind.CallOpts, registry *ens.Registry, node [32]byte) (*resolver.Contract, error) {
+	addr, err := registry.Contract.Resolver(opts, node)
+	if err != nil || addr == (common.Address{}) {
+		return nil, err
+	}
+	return resolver.NewContract(addr, e.client)
+}
+
+// Resolve returns the address a name resolves to
+func (e *ENS) Resolve(ctx context.Context, name string) (common.Address, error) {
+	if err := e.check(ctx); err != nil {
+		return common.Address{}, err
+	}
+	return ens.Resolve(e.client, name)
+}
+
+// ENSRecord holds the registration and records of a name
+type ENSRecord struct {
+	Name string
+	// Address is zero when the name doesn't resolve to an address
+	Address  common.Address
+	Owner    common.Address
+	Resolver common.Address
+	// Registrant and Expires are only set on second level .eth names
+	Registrant common.Address
+	Expires    time.Time
+	Texts      map[string]string
+	// Contenthash is decoded, e.g. to an ipfs:// url, when the codec is
+	// known and hex otherwise
+	Contenthash string
+}
+
+// Lookup returns the registration and records of a name
+func (e *ENS) Lookup(ctx context.Context, name string) (*ENSRecord, error) {
+	if err := e.check(ctx); err != nil {
+		return nil, err
+	}
+	name, err := ens.NormaliseDomain(name)
+	if err != nil {
+		return nil, err
+	}
+	registry, err := ens.NewRegistry(e.client)
+	if err != nil {
+		return nil, err
+	}
+	rec := &ENSRecord{Name: name, Texts: make(map[string]string)}
+	if rec.Owner, err = registry.Owner(name); err != nil {
+		return nil, err
+	}
+	if rec.Resolver, err = registry.ResolverAddress(name); err != nil {
+		return nil, err
+	}
+	if rec.Owner == (common.Address{}) && rec.Resolver == (common.Address{}) {
+		return nil, fmt.Errorf("%s is not registered", name)
+	}
+
+	if rec.Resolver != (common.Address{}) {
+		resolver, err := ens.NewResolverAt(e.client, name, rec.Resolver)
+		if err != nil {
+			return nil, err
+		}
+		rec.Address, _ = resolver.Address()
+		for _, key := range ENSTextKeys {
+			if text, err := resolver.Text(key); err == nil && text != "" {
+				rec.Texts[key] = text
+			}
+		}
+		if hash, err := resolver.Contenthash(); err == nil && len(hash) > 0 {
+			if rec.Contenthash, err = ens.ContenthashToString(hash); err != nil {
+				rec.Contenthash = hexutil.Encode(hash)
+			}
+		}
+	}
+
+	if ens.Tld(name) == "eth" && ens.DomainLevel(name) == 1 {
+		if n, err := ens.NewName(e.client, name); err == nil {
+			rec.Registrant, _ = n.Registrant()
+			if expires, err := n.Expires(); err == nil {
+				rec.Expires = expires
+			}
+		}
+	}
+	return rec, nil
+}
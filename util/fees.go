@@ -6,25 +6,91 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-func GetFee(rec *types.Receipt, txn *types.Transaction, baseFee *big.Int) *big.Int {
-	switch txn.Type() {
-	case 2:
-		return GetEIP1559Fee(rec, txn, baseFee)
-	case 0:
-		return GetLegacyFee(txn, rec)
-	default:
-		return big.NewInt(0)
-	}
+// Fees breaks down what a transaction paid, in wei
+type Fees struct {
+	// GasPrice is the effective price paid per unit of gas
+	GasPrice *big.Int
+	// Burnt is the base fee burnt for the gas used
+	Burnt *big.Int
+	// Tip is the priority fee paid to the block producer
+	Tip *big.Int
+	// Blob is the fee paid for blob gas, which is burnt as well
+	Blob *big.Int
+	// L1 is the fee rollups charge for posting the transaction to layer 1
+	L1    *big.Int
+	Total *big.Int
+}
 
+// EffectiveGasPrice returns the price per gas a transaction pays in a block
+// with the base fee, which is nil before London. Transactions with a fee
+// cap pay the base fee plus their tip, up to the cap.
+func EffectiveGasPrice(txn *types.Transaction, baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return new(big.Int).Set(txn.GasPrice())
+	}
+	// legacy transactions have their gas price as both cap and tip
+	price := new(big.Int).Add(baseFee, txn.GasTipCap())
+	if price.Cmp(txn.GasFeeCap()) > 0 {
+		price.Set(txn.GasFeeCap())
+	}
+	return price
 }
 
-func GetEIP1559Fee(rec *types.Receipt, txn *types.Transaction, baseFee *big.Int) *big.Int {
-	feeTip := new(big.Int).Add(baseFee, txn.GasTipCap())
-	return new(big.Int).Mul(feeTip, new(big.Int).SetUint64(rec.GasUsed))
+// GetFees computes the fees of a transaction from its receipt, preferring
+// the effective gas price reported by the node.
+func GetFees(rec *Receipt, txn *types.Transaction, baseFee *big.Int) Fees {
+	price := rec.EffectiveGasPrice
+	if price == nil {
+		price = EffectiveGasPrice(txn, baseFee)
+	}
+	gasUsed := new(big.Int).SetUint64(rec.GasUsed)
 
+	f := Fees{
+		GasPrice: price,
+		Burnt:    new(big.Int),
+		Tip:      new(big.Int).Mul(price, gasUsed),
+		Blob:     new(big.Int),
+		L1:       new(big.Int),
+	}
+	if baseFee != nil {
+		// system transactions may pay less than the base fee
+		burnt := baseFee
+		if price.Cmp(baseFee) < 0 {
+			burnt = price
+		}
+		f.Burnt.Mul(burnt, gasUsed)
+		f.Tip.Sub(f.Tip, f.Burnt)
+	}
+	if rec.BlobGasPrice != nil {
+		f.Blob.Mul(rec.BlobGasPrice, new(big.Int).SetUint64(rec.BlobGasUsed))
+	}
+	if rec.L1 != nil && rec.L1.Fee != nil {
+		f.L1.Set(rec.L1.Fee)
+	}
+	f.Total = new(big.Int).Mul(price, gasUsed)
+	f.Total.Add(f.Total, f.Blob)
+	f.Total.Add(f.Total, f.L1)
+	return f
 }
 
-func GetLegacyFee(txn *types.Transaction, rec *types.Receipt) *big.Int {
-	return new(big.Int).Mul(txn.GasPrice(), new(big.Int).SetUint64(rec.GasUsed))
+// Add sums the fees of another transaction, the gas price is left unset
+func (f Fees) Add(o Fees) Fees {
+	sum := func(a, b *big.Int) *big.Int {
+		if a == nil {
+			a = new(big.Int)
+		}
+		return new(big.Int).Add(a, b)
+	}
+	return Fees{
+		Burnt: sum(f.Burnt, o.Burnt),
+		Tip:   sum(f.Tip, o.Tip),
+		Blob:  sum(f.Blob, o.Blob),
+		L1:    sum(f.L1, o.L1),
+		Total: sum(f.Total, o.Total),
+	}
+}
 
+// GetFee returns the total fee paid by a transaction
+func GetFee(rec *Receipt, txn *types.Transaction, baseFee *big.Int) *big.Int {
+	return GetFees(rec, txn, baseFee).Total
 }
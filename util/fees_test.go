@@ -0,0 +1,104 @@
+package util
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+)
+
+func TestGetFees(t *testing.T) {
+	to := common.HexToAddress("0x01")
+	legacy := types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(20), Gas: 21000, To: &to})
+	dynamic := func(feeCap, tip int64) *types.Transaction {
+		return types.NewTx(&types.DynamicFeeTx{GasFeeCap: big.NewInt(feeCap), GasTipCap: big.NewInt(tip), Gas: 21000, To: &to})
+	}
+	blob := types.NewTx(&types.BlobTx{GasFeeCap: uint256.NewInt(30), GasTipCap: uint256.NewInt(2), Gas: 21000,
+		To: to, BlobFeeCap: uint256.NewInt(5), BlobHashes: []common.Hash{{0x01}}})
+	// deposits are stood in for by legacy transactions without a gas price
+	deposit := types.NewTx(&types.LegacyTx{GasPrice: new(big.Int), Gas: 1000000, To: &to})
+
+	tests := []struct {
+		name    string
+		txn     *types.Transaction
+		rec     *Receipt
+		baseFee *big.Int
+		want    Fees
+	}{
+		{
+			name:    "legacy before london",
+			txn:     legacy,
+			rec:     &Receipt{Receipt: &types.Receipt{GasUsed: 21000}},
+			baseFee: nil,
+			want:    Fees{GasPrice: big.NewInt(20), Burnt: big.NewInt(0), Tip: big.NewInt(420000), Blob: big.NewInt(0), L1: big.NewInt(0), Total: big.NewInt(420000)},
+		},
+		{
+			name:    "legacy",
+			txn:     legacy,
+			rec:     &Receipt{Receipt: &types.Receipt{GasUsed: 21000}},
+			baseFee: big.NewInt(15),
+			want:    Fees{GasPrice: big.NewInt(20), Burnt: big.NewInt(315000), Tip: big.NewInt(105000), Blob: big.NewInt(0), L1: big.NewInt(0), Total: big.NewInt(420000)},
+		},
+		{
+			name:    "dynamic fee uncapped",
+			txn:     dynamic(30, 2),
+			rec:     &Receipt{Receipt: &types.Receipt{GasUsed: 21000}},
+			baseFee: big.NewInt(15),
+			want:    Fees{GasPrice: big.NewInt(17), Burnt: big.NewInt(315000), Tip: big.NewInt(42000), Blob: big.NewInt(0), L1: big.NewInt(0), Total: big.NewInt(357000)},
+		},
+		{
+			name:    "dynamic fee capped",
+			txn:     dynamic(16, 2),
+			rec:     &Receipt{Receipt: &types.Receipt{GasUsed: 21000}},
+			baseFee: big.NewInt(15),
+			want:    Fees{GasPrice: big.NewInt(16), Burnt: big.NewInt(315000), Tip: big.NewInt(21000), Blob: big.NewInt(0), L1: big.NewInt(0), Total: big.NewInt(336000)},
+		},
+		{
+			name:    "effective gas price of the receipt",
+			txn:     dynamic(30, 2),
+			rec:     &Receipt{Receipt: &types.Receipt{GasUsed: 21000, EffectiveGasPrice: big.NewInt(16)}},
+			baseFee: big.NewInt(15),
+			want:    Fees{GasPrice: big.NewInt(16), Burnt: big.NewInt(315000), Tip: big.NewInt(21000), Blob: big.NewInt(0), L1: big.NewInt(0), Total: big.NewInt(336000)},
+		},
+		{
+			name:    "blob",
+			txn:     blob,
+			rec:     &Receipt{Receipt: &types.Receipt{GasUsed: 21000, BlobGasUsed: 131072, BlobGasPrice: big.NewInt(3)}},
+			baseFee: big.NewInt(15),
+			want:    Fees{GasPrice: big.NewInt(17), Burnt: big.NewInt(315000), Tip: big.NewInt(42000), Blob: big.NewInt(393216), L1: big.NewInt(0), Total: big.NewInt(750216)},
+		},
+		{
+			name:    "system deposit",
+			txn:     deposit,
+			rec:     &Receipt{Receipt: &types.Receipt{GasUsed: 46000}},
+			baseFee: big.NewInt(15),
+			want:    Fees{GasPrice: big.NewInt(0), Burnt: big.NewInt(0), Tip: big.NewInt(0), Blob: big.NewInt(0), L1: big.NewInt(0), Total: big.NewInt(0)},
+		},
+		{
+			name:    "l1 fee",
+			txn:     dynamic(30, 2),
+			rec:     &Receipt{Receipt: &types.Receipt{GasUsed: 21000}, L1: &L1Receipt{Fee: big.NewInt(1000)}},
+			baseFee: big.NewInt(15),
+			want:    Fees{GasPrice: big.NewInt(17), Burnt: big.NewInt(315000), Tip: big.NewInt(42000), Blob: big.NewInt(0), L1: big.NewInt(1000), Total: big.NewInt(358000)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetFees(tt.rec, tt.txn, tt.baseFee)
+			check := func(field string, got, want *big.Int) {
+				if got.Cmp(want) != 0 {
+					t.Errorf("%s = %s, want %s", field, got, want)
+				}
+			}
+			check("GasPrice", got.GasPrice, tt.want.GasPrice)
+			check("Burnt", got.Burnt, tt.want.Burnt)
+			check("Tip", got.Tip, tt.want.Tip)
+			check("Blob", got.Blob, tt.want.Blob)
+			check("L1", got.L1, tt.want.L1)
+			check("Total", got.Total, tt.want.Total)
+		})
+	}
+}
@@ -1,16 +1,14 @@
 package util
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/params"
-
-	ens "github.com/wealdtech/go-ens/v3"
 )
 
 func HexStripZeros(hex string) string {
@@ -22,16 +20,26 @@ func HexStripZeros(hex string) string {
 	return fmt.Sprintf("0x%s", trimmed)
 }
 
-func FormatAddress(client *ethclient.Client, addr common.Address) string {
-	known, ok := commonAddresses[strings.ToLower(addr.Hex())]
+// AddressKind describes where the display name of an address came from
+type AddressKind int
+
+const (
+	AddressPlain AddressKind = iota
+	AddressLabel
+	AddressENS
+)
+
+// LookupAddress returns the display name of an address, its known label on
+// the chain or verified ENS name, or the hex address.
+func LookupAddress(ctx context.Context, ens *ENS, chain *Chain, addr common.Address) (string, AddressKind) {
+	known, ok := chain.Label(addr.Hex())
 	if ok {
-		return fmt.Sprintf("[orange]%s[orange]", known)
+		return known, AddressLabel
 	}
-	name, err := ens.ReverseResolve(client, addr)
-	if err == nil {
-		return fmt.Sprintf("[blue]%s[blue]", name)
+	if name, err := ens.Name(ctx, addr); err == nil && name != "" {
+		return name, AddressENS
 	}
-	return addr.Hex()
+	return addr.Hex(), AddressPlain
 }
 
 func FormatTime(t time.Time) string {
@@ -62,3 +70,8 @@ func WeiToEther(wei *big.Int) *big.Float {
 	fWei.SetMode(big.ToNearestEven)
 	return f.Quo(fWei.SetInt(wei), big.NewFloat(params.Ether))
 }
+
+// GweiToWei converts amounts in gwei, such as withdrawals, to wei
+func GweiToWei(gwei uint64) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(gwei), big.NewInt(params.GWei))
+}
@@ -0,0 +1,101 @@
+package util
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// FeePercentiles are the priority fee percentiles requested from
+// eth_feeHistory, the estimates use them for slow, standard and fast
+var FeePercentiles = []float64{10, 50, 90}
+
+// FeeMarket holds the EIP-1559 parameters of a chain
+type FeeMarket struct {
+	// ElasticityMultiplier is the gas limit over the gas target
+	ElasticityMultiplier uint64
+	// BaseFeeChangeDenominator bounds the change of the base fee per block
+	BaseFeeChangeDenominator uint64
+}
+
+var (
+	MainnetFeeMarket = FeeMarket{
+		ElasticityMultiplier:     params.DefaultElasticityMultiplier,
+		BaseFeeChangeDenominator: params.DefaultBaseFeeChangeDenominator,
+	}
+	// OPStackFeeMarket is that of OP Stack chains since Canyon, the
+	// denominator was 50 before
+	OPStackFeeMarket = FeeMarket{ElasticityMultiplier: 6, BaseFeeChangeDenominator: 250}
+	// PolygonFeeMarket is that of polygon since the Delhi fork
+	PolygonFeeMarket = FeeMarket{ElasticityMultiplier: 2, BaseFeeChangeDenominator: 16}
+)
+
+// NextBaseFee computes the base fee of the block after the header as per
+// EIP-1559 with the parameters of the chain, nil before London
+func NextBaseFee(parent *types.Header, market FeeMarket) *big.Int {
+	if parent.BaseFee == nil || market.ElasticityMultiplier == 0 || market.BaseFeeChangeDenominator == 0 {
+		return nil
+	}
+	target := parent.GasLimit / market.ElasticityMultiplier
+	if target == 0 || parent.GasUsed == target {
+		return new(big.Int).Set(parent.BaseFee)
+	}
+
+	var diff uint64
+	if parent.GasUsed > target {
+		diff = parent.GasUsed - target
+	} else {
+		diff = target - parent.GasUsed
+	}
+	delta := new(big.Int).Mul(parent.BaseFee, new(big.Int).SetUint64(diff))
+	delta.Div(delta, new(big.Int).SetUint64(target))
+	delta.Div(delta, new(big.Int).SetUint64(market.BaseFeeChangeDenominator))
+
+	if parent.GasUsed > target {
+		// increases by at least 1 wei
+		if delta.Sign() == 0 {
+			delta.SetInt64(1)
+		}
+		return delta.Add(parent.BaseFee, delta)
+	}
+	next := new(big.Int).Sub(parent.BaseFee, delta)
+	if next.Sign() < 0 {
+		next.SetInt64(0)
+	}
+	return next
+}
+
+// FeeEstimate suggests the fees of a transaction
+type FeeEstimate struct {
+	MaxFee         *big.Int
+	MaxPriorityFee *big.Int
+}
+
+// EstimateFees suggests slow, standard and fast fees from a fee history
+// requested with FeePercentiles. The tips are the median of each percentile
+// over the history, and the fee caps allow the base fee to double.
+func EstimateFees(history *ethereum.FeeHistory, nextBaseFee *big.Int) []FeeEstimate {
+	estimates := make([]FeeEstimate, len(FeePercentiles))
+	for i := range FeePercentiles {
+		var tips []*big.Int
+		for _, rewards := range history.Reward {
+			if i < len(rewards) && rewards[i] != nil {
+				tips = append(tips, rewards[i])
+			}
+		}
+		tip := new(big.Int)
+		if len(tips) > 0 {
+			sort.Slice(tips, func(a, b int) bool { return tips[a].Cmp(tips[b]) < 0 })
+			tip.Set(tips[len(tips)/2])
+		}
+		maxFee := new(big.Int).Set(tip)
+		if nextBaseFee != nil {
+			maxFee.Add(maxFee, new(big.Int).Mul(nextBaseFee, big.NewInt(2)))
+		}
+		estimates[i] = FeeEstimate{MaxFee: maxFee, MaxPriorityFee: tip}
+	}
+	return estimates
+}
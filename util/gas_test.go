@@ -0,0 +1,46 @@
+package util
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestNextBaseFee(t *testing.T) {
+	tests := []struct {
+		name    string
+		market  FeeMarket
+		gasUsed uint64
+		baseFee *big.Int
+		want    *big.Int
+	}{
+		{name: "full block", market: MainnetFeeMarket, gasUsed: 30000000, baseFee: big.NewInt(1e9), want: big.NewInt(1125000000)},
+		{name: "above target", market: MainnetFeeMarket, gasUsed: 22500000, baseFee: big.NewInt(1e9), want: big.NewInt(1062500000)},
+		{name: "above target by the least", market: MainnetFeeMarket, gasUsed: 15000001, baseFee: big.NewInt(7), want: big.NewInt(8)},
+		{name: "at target", market: MainnetFeeMarket, gasUsed: 15000000, baseFee: big.NewInt(1e9), want: big.NewInt(1e9)},
+		{name: "below target", market: MainnetFeeMarket, gasUsed: 7500000, baseFee: big.NewInt(1e9), want: big.NewInt(937500000)},
+		{name: "empty block", market: MainnetFeeMarket, gasUsed: 0, baseFee: big.NewInt(1e9), want: big.NewInt(875000000)},
+		{name: "before london", market: MainnetFeeMarket, gasUsed: 15000000, baseFee: nil, want: nil},
+		{name: "op stack full block", market: OPStackFeeMarket, gasUsed: 30000000, baseFee: big.NewInt(1e9), want: big.NewInt(1020000000)},
+		{name: "op stack at target", market: OPStackFeeMarket, gasUsed: 5000000, baseFee: big.NewInt(1e9), want: big.NewInt(1e9)},
+		{name: "op stack empty block", market: OPStackFeeMarket, gasUsed: 0, baseFee: big.NewInt(1e9), want: big.NewInt(996000000)},
+		{name: "no parameters", gasUsed: 15000000, baseFee: big.NewInt(1e9), want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := &types.Header{GasLimit: 30000000, GasUsed: tt.gasUsed, BaseFee: tt.baseFee}
+			got := NextBaseFee(parent, tt.market)
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("NextBaseFee = %s, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.Cmp(tt.want) != 0 {
+				t.Errorf("NextBaseFee = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
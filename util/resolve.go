@@ -0,0 +1,245 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RefKind is the kind of thing a search input refers to.
+type RefKind int
+
+const (
+	RefBlock RefKind = iota
+	RefTxn
+	RefAddress
+	RefSelector
+	RefTopic
+)
+
+func (k RefKind) String() string {
+	switch k {
+	case RefBlock:
+		return "block"
+	case RefTxn:
+		return "transaction"
+	case RefAddress:
+		return "address"
+	case RefSelector:
+		return "function selector"
+	case RefTopic:
+		return "event topic"
+	}
+	return "unknown"
+}
+
+// Ref is a resolved reference to a block, transaction, address or
+// signature.
+type Ref struct {
+	Kind  RefKind
+	Input string
+	// Hash is the block or transaction hash
+	Hash common.Hash
+	// Number is the block number
+	Number  *big.Int
+	Address common.Address
+	// Name is the ENS name of an address, or the text signature of a
+	// selector or topic
+	Name string
+}
+
+func (r Ref) String() string {
+	switch r.Kind {
+	case RefBlock:
+		return fmt.Sprintf("block %s (%s)", r.Number, r.Hash.Hex())
+	case RefTxn:
+		return fmt.Sprintf("transaction %s", r.Hash.Hex())
+	case RefAddress:
+		if r.Name != "" {
+			return fmt.Sprintf("address %s (%s)", r.Address.Hex(), r.Name)
+		}
+		return fmt.Sprintf("address %s", r.Address.Hex())
+	case RefSelector, RefTopic:
+		return fmt.Sprintf("%s %s: %s", r.Kind, r.Input, r.Name)
+	}
+	return r.Input
+}
+
+var (
+	decimalRe  = regexp.MustCompile(`^[0-9]+$`)
+	hexRe      = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
+	selectorRe = regexp.MustCompile(`^0x[0-9a-fA-F]{8}$`)
+)
+
+// Classify returns the kinds of reference the input may be, without
+// checking whether they exist.
+func Classify(input string) []RefKind {
+	input = strings.TrimSpace(input)
+	lower := strings.ToLower(input)
+	for _, tag := range blockTags {
+		if lower == tag {
+			return []RefKind{RefBlock}
+		}
+	}
+	switch {
+	case decimalRe.MatchString(input):
+		return []RefKind{RefBlock}
+	case selectorRe.MatchString(input):
+		// also a valid hex block number
+		return []RefKind{RefBlock, RefSelector}
+	case hexRe.MatchString(input) && len(input) == 66:
+		return []RefKind{RefBlock, RefTxn, RefTopic}
+	case common.IsHexAddress(input) && strings.HasPrefix(input, "0x"):
+		return []RefKind{RefAddress}
+	case hexRe.MatchString(input) && len(input) <= 18:
+		return []RefKind{RefBlock}
+	case strings.Contains(input, ".") && !strings.ContainsAny(input, " /"):
+		return []RefKind{RefAddress}
+	}
+	return nil
+}
+
+func isKind(input string, kind RefKind) bool {
+	for _, k := range Classify(input) {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolver looks up search input against the node, ENS and the signature
+// database.
+type Resolver struct {
+	client *Client
+	rpc    *rpc.Client
+	sigs   *SignatureDB
+	ens    *ENS
+}
+
+func NewResolver(client *Client, rpcClient *rpc.Client, ens *ENS) *Resolver {
+	return &Resolver{
+		client: client,
+		rpc:    rpcClient,
+		sigs:   NewSignatureDB(),
+		ens:    ens,
+	}
+}
+
+// Resolve returns every reference the input matches. An error is only
+// returned when nothing matched.
+func (r *Resolver) Resolve(ctx context.Context, input string) ([]Ref, error) {
+	input = strings.TrimSpace(input)
+	kinds := Classify(input)
+	if len(kinds) == 0 {
+		return nil, fmt.Errorf("unrecognized input: %s", input)
+	}
+
+	// look up every candidate kind concurrently, keeping their order
+	results := make([]*Ref, len(kinds))
+	failures := make([]error, len(kinds))
+	var wg sync.WaitGroup
+	for i, kind := range kinds {
+		wg.Add(1)
+		go func(i int, kind RefKind) {
+			defer wg.Done()
+			results[i], failures[i] = r.ResolveKind(ctx, kind, input)
+		}(i, kind)
+	}
+	wg.Wait()
+
+	var refs []Ref
+	var errs []string
+	for i, ref := range results {
+		if failures[i] != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", kinds[i], failures[i]))
+			continue
+		}
+		refs = append(refs, *ref)
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("no match for %s (%s)", input, strings.Join(errs, ", "))
+	}
+	return refs, nil
+}
+
+// ResolveKind resolves the input as a specific kind of reference.
+func (r *Resolver) ResolveKind(ctx context.Context, kind RefKind, input string) (*Ref, error) {
+	input = strings.TrimSpace(input)
+	if !isKind(input, kind) {
+		return nil, fmt.Errorf("not a valid %s: %s", kind, input)
+	}
+	ref := &Ref{Kind: kind, Input: input}
+	switch kind {
+	case RefBlock:
+		header, err := r.header(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		ref.Hash = header.Hash()
+		ref.Number = header.Number
+
+	case RefTxn:
+		h := common.HexToHash(input)
+		if _, _, err := r.client.TransactionByHash(ctx, h); err != nil {
+			return nil, err
+		}
+		ref.Hash = h
+
+	case RefAddress:
+		if common.IsHexAddress(input) {
+			ref.Address = common.HexToAddress(input)
+			ref.Name, _ = r.ens.Name(ctx, ref.Address)
+			break
+		}
+		addr, err := r.ens.Resolve(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		ref.Address = addr
+		ref.Name = input
+
+	case RefSelector:
+		sig, err := r.sigs.GetSignature(strings.ToLower(input))
+		if err != nil {
+			return nil, err
+		}
+		ref.Name = sig.TextSignature
+
+	case RefTopic:
+		sig, err := r.sigs.GetEventSignature(strings.ToLower(input))
+		if err != nil {
+			return nil, err
+		}
+		ref.Name = sig.TextSignature
+	}
+	return ref, nil
+}
+
+// header fetches a block header by hash, number or tag
+func (r *Resolver) header(ctx context.Context, input string) (*types.Header, error) {
+	if len(input) == 66 {
+		return r.client.HeaderByHash(ctx, common.HexToHash(input))
+	}
+	tag, err := ParseBlockTag(input)
+	if err != nil {
+		return nil, err
+	}
+	// fetch by tag directly so safe and finalized resolve as well
+	var header *types.Header
+	if err := r.rpc.CallContext(ctx, &header, "eth_getBlockByNumber", tag, false); err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, ethereum.NotFound
+	}
+	return header, nil
+}
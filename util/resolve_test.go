@@ -0,0 +1,35 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []RefKind
+	}{
+		{input: "latest", want: []RefKind{RefBlock}},
+		{input: " Finalized ", want: []RefKind{RefBlock}},
+		{input: "17000000", want: []RefKind{RefBlock}},
+		{input: "0x10", want: []RefKind{RefBlock}},
+		{input: "0xa9059cbb", want: []RefKind{RefBlock, RefSelector}},
+		{input: "0x4a1c5b7c3b0f2e8d9c6b5a4f3e2d1c0b0a99887766554433221100ffeeddccbb", want: []RefKind{RefBlock, RefTxn, RefTopic}},
+		{input: "0x000000000000000000000000000000000000dEaD", want: []RefKind{RefAddress}},
+		{input: "vitalik.eth", want: []RefKind{RefAddress}},
+		{input: "000000000000000000000000000000000000dEaD"},
+		{input: "0x4a1c5b7c3b0f2e8d9c6b5a4f3e2d1c0b0a9988776655"},
+		{input: "not a.name"},
+		{input: "transfer"},
+		{input: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Classify(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Classify(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
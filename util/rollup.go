@@ -0,0 +1,294 @@
+package util
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Transaction types of rollups that go-ethereum can't decode
+const (
+	DepositTxType                 = 0x7e
+	ArbitrumDepositTxType         = 0x64
+	ArbitrumUnsignedTxType        = 0x65
+	ArbitrumContractTxType        = 0x66
+	ArbitrumRetryTxType           = 0x68
+	ArbitrumSubmitRetryableTxType = 0x69
+	ArbitrumInternalTxType        = 0x6a
+	ArbitrumLegacyTxType          = 0x78
+)
+
+// l1BlockAddress is the OP Stack predeploy the first deposit of every block
+// sets the l1 block values of, sent by l1AttributesDepositor
+var (
+	l1BlockAddress        = common.HexToAddress("0x4200000000000000000000000000000000000015")
+	l1AttributesDepositor = common.HexToAddress("0xDeaDDEaDDeAdDeAdDEAdDEaddeAddEAdDEAd0001")
+)
+
+// selectors of the l1 block values setters, from bedrock on
+var (
+	setL1BlockValues        = hexutil.MustDecode("0x015d8eb9")
+	setL1BlockValuesEcotone = hexutil.MustDecode("0x440a5e20")
+	setL1BlockValuesIsthmus = hexutil.MustDecode("0x098999be")
+)
+
+// RollupTx holds a rollup transaction of a type go-ethereum can't decode.
+// It is stood in for by an unsigned legacy transaction with the same nonce,
+// gas, recipient, value and data, whose hash differs (see RollupTxs.TxHash).
+type RollupTx struct {
+	Type uint8
+	Hash common.Hash
+	From common.Address
+	// SourceHash and Mint are set on OP Stack deposits
+	SourceHash *common.Hash
+	Mint       *big.Int
+	// IsSystem marks the l1 attributes and system deposits of OP Stack
+	// chains and arbitrum internal transactions
+	IsSystem bool
+}
+
+// Label describes the transaction in place of a method
+func (r *RollupTx) Label() string {
+	switch {
+	case r.IsSystem:
+		return "System"
+	case r.Type == DepositTxType, r.Type == ArbitrumDepositTxType:
+		return "Deposit"
+	case r.Type == ArbitrumRetryTxType:
+		return "Retry"
+	case r.Type == ArbitrumSubmitRetryableTxType:
+		return "SubmitRetryable"
+	}
+	return ""
+}
+
+// maxRollupTxs is the number of rollup transactions kept before the oldest
+// are dropped
+const maxRollupTxs = 100000
+
+// RollupTxs is the side table of the rollup transactions decoded by a
+// Client, keyed by the hash of their stand-ins. A stand-in's hash follows
+// from its fields, so every decoding of a transaction finds the same entry.
+// A nil table holds no rollup transactions.
+type RollupTxs struct {
+	mu  sync.Mutex
+	txs map[common.Hash]*RollupTx
+	// keys in the order they were added, to drop the oldest
+	keys []common.Hash
+}
+
+func NewRollupTxs() *RollupTxs {
+	return &RollupTxs{txs: make(map[common.Hash]*RollupTx)}
+}
+
+func (t *RollupTxs) add(txn *types.Transaction, r *RollupTx) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := txn.Hash()
+	if _, ok := t.txs[key]; !ok {
+		if len(t.keys) >= maxRollupTxs {
+			delete(t.txs, t.keys[0])
+			t.keys = t.keys[1:]
+		}
+		t.keys = append(t.keys, key)
+	}
+	t.txs[key] = r
+}
+
+// Rollup returns the rollup transaction the transaction stands in for
+func (t *RollupTxs) Rollup(txn *types.Transaction) (*RollupTx, bool) {
+	if t == nil {
+		return nil, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r, ok := t.txs[txn.Hash()]
+	return r, ok
+}
+
+// TxHash returns the hash of the transaction, or of the rollup transaction
+// it stands in for
+func (t *RollupTxs) TxHash(txn *types.Transaction) common.Hash {
+	if r, ok := t.Rollup(txn); ok {
+		return r.Hash
+	}
+	return txn.Hash()
+}
+
+// TxType returns the type of the transaction, or of the rollup transaction
+// it stands in for
+func (t *RollupTxs) TxType(txn *types.Transaction) uint8 {
+	if r, ok := t.Rollup(txn); ok {
+		return r.Type
+	}
+	return txn.Type()
+}
+
+type rollupTxJSON struct {
+	Type       hexutil.Uint64  `json:"type"`
+	Hash       common.Hash     `json:"hash"`
+	From       common.Address  `json:"from"`
+	To         *common.Address `json:"to"`
+	Nonce      hexutil.Uint64  `json:"nonce"`
+	Gas        hexutil.Uint64  `json:"gas"`
+	GasPrice   *hexutil.Big    `json:"gasPrice"`
+	Value      *hexutil.Big    `json:"value"`
+	Input      hexutil.Bytes   `json:"input"`
+	SourceHash *common.Hash    `json:"sourceHash"`
+	Mint       *hexutil.Big    `json:"mint"`
+	IsSystemTx bool            `json:"isSystemTx"`
+}
+
+// decodeRollupTx decodes a transaction of an unknown type into an unsigned
+// legacy stand-in and the rollup transaction it stands in for
+func decodeRollupTx(raw json.RawMessage) (*types.Transaction, *RollupTx, error) {
+	var dec rollupTxJSON
+	if err := json.Unmarshal(raw, &dec); err != nil {
+		return nil, nil, err
+	}
+	orZero := func(b *hexutil.Big) *big.Int {
+		if b == nil {
+			return new(big.Int)
+		}
+		return b.ToInt()
+	}
+	txn := types.NewTx(&types.LegacyTx{
+		Nonce:    uint64(dec.Nonce),
+		GasPrice: orZero(dec.GasPrice),
+		Gas:      uint64(dec.Gas),
+		To:       dec.To,
+		Value:    orZero(dec.Value),
+		Data:     dec.Input,
+		V:        new(big.Int),
+		R:        new(big.Int),
+		S:        new(big.Int),
+	})
+
+	r := &RollupTx{
+		Type:       uint8(dec.Type),
+		Hash:       dec.Hash,
+		From:       dec.From,
+		SourceHash: dec.SourceHash,
+		IsSystem:   dec.IsSystemTx || dec.From == l1AttributesDepositor || dec.Type == ArbitrumInternalTxType,
+	}
+	if dec.Mint != nil {
+		r.Mint = dec.Mint.ToInt()
+	}
+	return txn, r, nil
+}
+
+// L1Block is the layer 1 block a rollup block was derived from. Only the
+// number is known on arbitrum.
+type L1Block struct {
+	Number  uint64
+	Time    uint64
+	Hash    common.Hash
+	BaseFee *big.Int
+}
+
+// L1Origin returns the layer 1 block of a rollup block, from the l1
+// attributes deposit of OP Stack blocks or the header of arbitrum blocks.
+func (t *RollupTxs) L1Origin(block *types.Block) (*L1Block, bool) {
+	txns := block.Transactions()
+	if len(txns) == 0 {
+		return nil, false
+	}
+	first, ok := t.Rollup(txns[0])
+	if !ok {
+		return nil, false
+	}
+	switch first.Type {
+	case ArbitrumInternalTxType:
+		return &L1Block{Number: binary.BigEndian.Uint64(block.MixDigest().Bytes()[8:16])}, true
+	case DepositTxType:
+		if txns[0].To() == nil || *txns[0].To() != l1BlockAddress {
+			return nil, false
+		}
+		return decodeL1Attributes(txns[0].Data())
+	}
+	return nil, false
+}
+
+func decodeL1Attributes(data []byte) (*L1Block, bool) {
+	if len(data) < 4 {
+		return nil, false
+	}
+	selector, args := data[:4], data[4:]
+	switch {
+	case string(selector) == string(setL1BlockValues) && len(args) >= 128:
+		// abi encoded number, timestamp, basefee, hash, ...
+		return &L1Block{
+			Number:  new(big.Int).SetBytes(args[0:32]).Uint64(),
+			Time:    new(big.Int).SetBytes(args[32:64]).Uint64(),
+			BaseFee: new(big.Int).SetBytes(args[64:96]),
+			Hash:    common.BytesToHash(args[96:128]),
+		}, true
+	case (string(selector) == string(setL1BlockValuesEcotone) ||
+		string(selector) == string(setL1BlockValuesIsthmus)) && len(args) >= 128:
+		// packed fee scalars (8), sequence number (8), timestamp (8),
+		// number (8), basefee (32), blob basefee (32), hash (32), ...
+		return &L1Block{
+			Time:    binary.BigEndian.Uint64(args[16:24]),
+			Number:  binary.BigEndian.Uint64(args[24:32]),
+			BaseFee: new(big.Int).SetBytes(args[32:64]),
+			Hash:    common.BytesToHash(args[96:128]),
+		}, true
+	}
+	return nil, false
+}
+
+// L1Receipt holds the layer 1 fields of a rollup receipt
+type L1Receipt struct {
+	// Fee is the l1 data fee charged on top of the gas of OP Stack
+	// transactions
+	Fee         *big.Int
+	GasUsed     *big.Int
+	GasPrice    *big.Int
+	BlobBaseFee *big.Int
+	FeeScalar   string
+	// BlockNumber is the l1 block of arbitrum transactions
+	BlockNumber *big.Int
+}
+
+type l1ReceiptJSON struct {
+	L1Fee         *hexutil.Big `json:"l1Fee"`
+	L1GasUsed     *hexutil.Big `json:"l1GasUsed"`
+	L1GasPrice    *hexutil.Big `json:"l1GasPrice"`
+	L1BlobBaseFee *hexutil.Big `json:"l1BlobBaseFee"`
+	L1FeeScalar   string       `json:"l1FeeScalar"`
+	GasUsedForL1  *hexutil.Big `json:"gasUsedForL1"`
+	L1BlockNumber *hexutil.Big `json:"l1BlockNumber"`
+}
+
+func decodeL1Receipt(raw json.RawMessage) (*L1Receipt, error) {
+	var dec l1ReceiptJSON
+	if err := json.Unmarshal(raw, &dec); err != nil {
+		return nil, err
+	}
+	toInt := func(b *hexutil.Big) *big.Int {
+		if b == nil {
+			return nil
+		}
+		return b.ToInt()
+	}
+	r := &L1Receipt{
+		Fee:         toInt(dec.L1Fee),
+		GasUsed:     toInt(dec.L1GasUsed),
+		GasPrice:    toInt(dec.L1GasPrice),
+		BlobBaseFee: toInt(dec.L1BlobBaseFee),
+		FeeScalar:   dec.L1FeeScalar,
+		BlockNumber: toInt(dec.L1BlockNumber),
+	}
+	if r.GasUsed == nil {
+		r.GasUsed = toInt(dec.GasUsedForL1)
+	}
+	if r.Fee == nil && r.GasUsed == nil && r.BlockNumber == nil {
+		return nil, nil
+	}
+	return r, nil
+}
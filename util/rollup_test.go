@@ -0,0 +1,112 @@
+package util
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const l1Hash = "0x4a1c5b7c3b0f2e8d9c6b5a4f3e2d1c0b0a99887766554433221100ffeeddccbb"
+
+// setL1BlockValues calldata of the l1 attributes deposit, one field per line
+var (
+	bedrockAttributes = "0x015d8eb9" +
+		"0000000000000000000000000000000000000000000000000000000001036640" + // number
+		"0000000000000000000000000000000000000000000000000000000064320640" + // timestamp
+		"00000000000000000000000000000000000000000000000000000006fc23ac00" + // basefee
+		"4a1c5b7c3b0f2e8d9c6b5a4f3e2d1c0b0a99887766554433221100ffeeddccbb" + // hash
+		"0000000000000000000000000000000000000000000000000000000000000002" + // sequence number
+		"0000000000000000000000006887246668a3b87f54deb3b94ba47a6f63f32985" + // batcher hash
+		"00000000000000000000000000000000000000000000000000000000000000bc" + // fee overhead
+		"00000000000000000000000000000000000000000000000000000000000a6fe0" // fee scalar
+	ecotoneAttributes = "0x440a5e20" +
+		"00000558" + "000c5fc5" + // basefee and blob basefee scalars
+		"0000000000000003" + // sequence number
+		"0000000065ec8780" + // timestamp
+		"0000000001280540" + // number
+		"00000000000000000000000000000000000000000000000000000005d21dba00" + // basefee
+		"0000000000000000000000000000000000000000000000000000000000000001" + // blob basefee
+		"4a1c5b7c3b0f2e8d9c6b5a4f3e2d1c0b0a99887766554433221100ffeeddccbb" + // hash
+		"0000000000000000000000006887246668a3b87f54deb3b94ba47a6f63f32985" // batcher hash
+)
+
+func TestDecodeL1Attributes(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want *L1Block
+	}{
+		{
+			name: "bedrock",
+			data: bedrockAttributes,
+			want: &L1Block{Number: 17000000, Time: 1681000000, BaseFee: big.NewInt(30000000000), Hash: common.HexToHash(l1Hash)},
+		},
+		{
+			name: "ecotone",
+			data: ecotoneAttributes,
+			want: &L1Block{Number: 19400000, Time: 1710000000, BaseFee: big.NewInt(25000000000), Hash: common.HexToHash(l1Hash)},
+		},
+		{name: "truncated", data: bedrockAttributes[:74]},
+		{name: "other selector", data: "0xa9059cbb" + bedrockAttributes[10:]},
+		{name: "no selector", data: "0x015d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := decodeL1Attributes(hexutil.MustDecode(tt.data))
+			if tt.want == nil {
+				if ok {
+					t.Fatalf("decodeL1Attributes = %+v, want none", got)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("decodeL1Attributes found no l1 block")
+			}
+			if got.Number != tt.want.Number || got.Time != tt.want.Time || got.Hash != tt.want.Hash ||
+				got.BaseFee.Cmp(tt.want.BaseFee) != 0 {
+				t.Errorf("decodeL1Attributes = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRollupTxs(t *testing.T) {
+	deposit := `{"type": "0x7e", "hash": "` + l1Hash + `", "from": "0xdeaddeaddeaddeaddeaddeaddeaddeaddead0001",
+		"to": "0x4200000000000000000000000000000000000015", "nonce": "0x1", "gas": "0xf4240", "value": "0x0",
+		"input": "` + bedrockAttributes + `", "sourceHash": "` + l1Hash + `", "isSystemTx": false}`
+	txn, r, err := decodeRollupTx([]byte(deposit))
+	if err != nil {
+		t.Fatalf("decodeRollupTx: %v", err)
+	}
+	if !r.IsSystem || r.Label() != "System" {
+		t.Errorf("l1 attributes deposit is not a system transaction: %+v", r)
+	}
+
+	var none *RollupTxs
+	if _, ok := none.Rollup(txn); ok || none.TxHash(txn) != txn.Hash() || none.TxType(txn) != types.LegacyTxType {
+		t.Error("nil table has a rollup transaction")
+	}
+
+	rollups := NewRollupTxs()
+	rollups.add(txn, r)
+	// a later decoding of the same transaction finds the entry
+	again, _, _ := decodeRollupTx([]byte(deposit))
+	if got, ok := rollups.Rollup(again); !ok || got != r {
+		t.Fatalf("Rollup = %+v, want %+v", got, r)
+	}
+	if got := rollups.TxHash(again); got != common.HexToHash(l1Hash) {
+		t.Errorf("TxHash = %s, want %s", got.Hex(), l1Hash)
+	}
+	if got := rollups.TxType(again); got != DepositTxType {
+		t.Errorf("TxType = %#x, want %#x", got, DepositTxType)
+	}
+
+	other := types.NewTx(&types.LegacyTx{Nonce: 2})
+	if _, ok := rollups.Rollup(other); ok || rollups.TxHash(other) != other.Hash() {
+		t.Error("found a rollup transaction for a regular one")
+	}
+}
@@ -0,0 +1,108 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxSenders is the number of senders kept before the cache is reset
+const maxSenders = 10000
+
+// Senders recovers the senders of transactions and caches them by hash.
+// Transactions without a signature, such as the deposits and system
+// transactions of rollups, use the sender reported by the node instead.
+type Senders struct {
+	signer  types.Signer
+	rpc     *rpc.Client
+	rollups *RollupTxs
+	mu      sync.Mutex
+	cache   map[common.Hash]common.Address
+}
+
+// NewSenders uses the latest signer of the chain, which accepts every
+// transaction type the chain has had. The senders of rollup transactions
+// are taken from the table of their client.
+func NewSenders(rpc *rpc.Client, chainID *big.Int, rollups *RollupTxs) *Senders {
+	return &Senders{
+		signer:  types.LatestSignerForChainID(chainID),
+		rpc:     rpc,
+		rollups: rollups,
+		cache:   make(map[common.Hash]common.Address),
+	}
+}
+
+// Signer returns the signer senders are recovered with
+func (s *Senders) Signer() types.Signer {
+	return s.signer
+}
+
+// Set records the sender of a transaction, e.g. the from field returned
+// along with it by the node
+func (s *Senders) Set(hash common.Hash, from common.Address) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cache) >= maxSenders {
+		s.cache = make(map[common.Hash]common.Address)
+	}
+	s.cache[hash] = from
+}
+
+func (s *Senders) cached(hash common.Hash) (common.Address, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	from, ok := s.cache[hash]
+	return from, ok
+}
+
+// Sender returns the sender of the transaction, recovered from its
+// signature, or asked of the node when it is unsigned or its signature
+// can't be recovered.
+func (s *Senders) Sender(ctx context.Context, txn *types.Transaction) (common.Address, error) {
+	if r, ok := s.rollups.Rollup(txn); ok {
+		return r.From, nil
+	}
+	if from, ok := s.cached(txn.Hash()); ok {
+		return from, nil
+	}
+	var from common.Address
+	var err error
+	if Unsigned(txn) {
+		from, err = s.fetch(ctx, txn.Hash())
+	} else if from, err = types.Sender(s.signer, txn); err != nil {
+		if fetched, ferr := s.fetch(ctx, txn.Hash()); ferr == nil {
+			from, err = fetched, nil
+		}
+	}
+	if err != nil {
+		return common.Address{}, err
+	}
+	s.Set(txn.Hash(), from)
+	return from, nil
+}
+
+// fetch gets the from field of a transaction from the node
+func (s *Senders) fetch(ctx context.Context, hash common.Hash) (common.Address, error) {
+	var txn struct {
+		From *common.Address `json:"from"`
+	}
+	if err := s.rpc.CallContext(ctx, &txn, "eth_getTransactionByHash", hash); err != nil {
+		return common.Address{}, err
+	}
+	if txn.From == nil {
+		return common.Address{}, fmt.Errorf("node has no sender for transaction %s", hash.Hex())
+	}
+	return *txn.From, nil
+}
+
+// Unsigned reports whether the transaction has no signature, as with
+// system transactions
+func Unsigned(txn *types.Transaction) bool {
+	_, r, s := txn.RawSignatureValues()
+	return (r == nil || r.Sign() == 0) && (s == nil || s.Sign() == 0)
+}
@@ -40,8 +40,16 @@ func NewSignatureDB() *SignatureDB {
 }
 
 func (db *SignatureDB) GetSignature(hex string) (*Signature, error) {
-	endpoint := fmt.Sprintf("%s/api/v1/signatures/", db.baseURL)
+	return db.lookup("signatures", hex)
+}
+
+// GetEventSignature looks up the event signature of a 32 byte log topic
+func (db *SignatureDB) GetEventSignature(hex string) (*Signature, error) {
+	return db.lookup("event-signatures", hex)
+}
 
+func (db *SignatureDB) lookup(kind, hex string) (*Signature, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/%s/", db.baseURL, kind)
 	url, err := url.Parse(endpoint)
 	if err != nil {
 		return nil, err
@@ -59,6 +67,7 @@ func (db *SignatureDB) GetSignature(hex string) (*Signature, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		return nil, errors.New("request failed")
@@ -70,7 +79,6 @@ func (db *SignatureDB) GetSignature(hex string) (*Signature, error) {
 		return nil, err
 	}
 
-	ioutil.WriteFile("sigresp.json", data, 0755)
 	err = json.Unmarshal(data, sigResp)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,261 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// balance given to the sender when funding is requested, large enough for
+// any value and gas but small enough to avoid overflows in the node
+var simulatedBalance = new(big.Int).Lsh(big.NewInt(1), 128)
+
+// selector of the Panic(uint256) error emitted by solidity assertions
+var panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+
+// CallMsg describes a message call to simulate.
+type CallMsg struct {
+	From  common.Address
+	To    *common.Address
+	Value *big.Int
+	Gas   uint64
+	Data  []byte
+}
+
+func (m CallMsg) toArg() map[string]interface{} {
+	arg := map[string]interface{}{
+		"from": m.From,
+		"to":   m.To,
+	}
+	if len(m.Data) > 0 {
+		arg["data"] = hexutil.Bytes(m.Data)
+	}
+	if m.Value != nil {
+		arg["value"] = (*hexutil.Big)(m.Value)
+	}
+	if m.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(m.Gas)
+	}
+	return arg
+}
+
+// SimulatedLog is a log emitted during a simulated call.
+type SimulatedLog struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+}
+
+// SimulationResult is the outcome of a simulated call.
+type SimulationResult struct {
+	ReturnData  []byte
+	Reverted    bool
+	Error       string
+	RevertData  []byte
+	GasUsed     uint64
+	GasEstimate uint64
+	EstimateErr error
+	Logs        []SimulatedLog
+	// Traced is set when the result came from debug_traceCall, in which
+	// case gas used and logs are available
+	Traced bool
+	// TraceErr is why debug_traceCall failed when falling back to eth_call
+	TraceErr error
+}
+
+// callFrame is the output of geth's callTracer
+type callFrame struct {
+	Type         string         `json:"type"`
+	From         common.Address `json:"from"`
+	To           common.Address `json:"to"`
+	Gas          hexutil.Uint64 `json:"gas"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	Output       hexutil.Bytes  `json:"output"`
+	Error        string         `json:"error"`
+	RevertReason string         `json:"revertReason"`
+	Calls        []callFrame    `json:"calls"`
+	Logs         []frameLog     `json:"logs"`
+}
+
+// frameLog is a log of a call frame, position being the number of subcalls
+// the frame made before emitting it
+type frameLog struct {
+	SimulatedLog
+	Position hexutil.Uint `json:"position"`
+}
+
+// collectLogs returns the logs of the frame and its successful subcalls in
+// execution order. Nodes not reporting log positions have the logs of a
+// frame placed before those of its subcalls.
+func (f callFrame) collectLogs() []SimulatedLog {
+	if f.Error != "" {
+		return nil
+	}
+	var logs []SimulatedLog
+	next := 0
+	for i, c := range f.Calls {
+		for ; next < len(f.Logs) && int(f.Logs[next].Position) <= i; next++ {
+			logs = append(logs, f.Logs[next].SimulatedLog)
+		}
+		logs = append(logs, c.collectLogs()...)
+	}
+	for ; next < len(f.Logs); next++ {
+		logs = append(logs, f.Logs[next].SimulatedLog)
+	}
+	return logs
+}
+
+func (f *callFrame) result() *SimulationResult {
+	res := &SimulationResult{
+		ReturnData: f.Output,
+		GasUsed:    uint64(f.GasUsed),
+		Logs:       f.collectLogs(),
+		Traced:     true,
+	}
+	if f.Error != "" {
+		res.Reverted = true
+		res.Error = f.Error
+		res.RevertData = f.Output
+		if f.RevertReason != "" {
+			res.Error = fmt.Sprintf("%s: %s", f.Error, f.RevertReason)
+		}
+	}
+	return res
+}
+
+func stateOverrides(msg CallMsg, fundSender bool) map[common.Address]interface{} {
+	if !fundSender {
+		return nil
+	}
+	return map[common.Address]interface{}{
+		msg.From: map[string]interface{}{
+			"balance": (*hexutil.Big)(simulatedBalance),
+		},
+	}
+}
+
+// Simulate runs the message against the state at the given block without
+// broadcasting anything. debug_traceCall is used when the node supports it,
+// otherwise it falls back to eth_call. If fundSender is set the sender's
+// balance is overridden so value transfers and gas can be paid for.
+func Simulate(ctx context.Context, client *rpc.Client, msg CallMsg, block string, fundSender bool) (*SimulationResult, error) {
+	overrides := stateOverrides(msg, fundSender)
+
+	res, traceErr := traceCall(ctx, client, msg, block, overrides)
+	if traceErr != nil {
+		var err error
+		res, err = ethCall(ctx, client, msg, block, overrides)
+		if err != nil {
+			return nil, err
+		}
+		res.TraceErr = traceErr
+	}
+
+	args := []interface{}{msg.toArg(), block}
+	if overrides != nil {
+		args = append(args, overrides)
+	}
+	var estimate hexutil.Uint64
+	res.EstimateErr = client.CallContext(ctx, &estimate, "eth_estimateGas", args...)
+	res.GasEstimate = uint64(estimate)
+	return res, nil
+}
+
+func traceCall(ctx context.Context, client *rpc.Client, msg CallMsg, block string, overrides map[common.Address]interface{}) (*SimulationResult, error) {
+	config := map[string]interface{}{
+		"tracer":       "callTracer",
+		"tracerConfig": map[string]interface{}{"withLog": true},
+	}
+	if overrides != nil {
+		config["stateOverrides"] = overrides
+	}
+
+	frame := &callFrame{}
+	err := client.CallContext(ctx, frame, "debug_traceCall", msg.toArg(), block, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return frame.result(), nil
+}
+
+func ethCall(ctx context.Context, client *rpc.Client, msg CallMsg, block string, overrides map[common.Address]interface{}) (*SimulationResult, error) {
+	args := []interface{}{msg.toArg(), block}
+	if overrides != nil {
+		args = append(args, overrides)
+	}
+
+	var result hexutil.Bytes
+	err := client.CallContext(ctx, &result, "eth_call", args...)
+	if err == nil {
+		return &SimulationResult{ReturnData: result}, nil
+	}
+
+	// reverts are reported as json-rpc errors carrying the revert data
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		if strings.Contains(err.Error(), "revert") {
+			return &SimulationResult{Reverted: true, Error: err.Error()}, nil
+		}
+		return nil, err
+	}
+	res := &SimulationResult{Reverted: true, Error: err.Error()}
+	if hexData, ok := dataErr.ErrorData().(string); ok {
+		res.RevertData, _ = hexutil.Decode(hexData)
+	}
+	return res, nil
+}
+
+// DecodeRevert returns a human readable reason for revert data, using the
+// standard Error(string) and Panic(uint256) encodings or custom errors
+// from the contract abi if available.
+func DecodeRevert(contractABI *abi.ABI, data []byte) string {
+	if len(data) < 4 {
+		return ""
+	}
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+	if bytes.Equal(data[:4], panicSelector) && len(data) == 36 {
+		code := new(big.Int).SetBytes(data[4:])
+		return fmt.Sprintf("panic: 0x%x", code)
+	}
+	if contractABI != nil {
+		for _, e := range contractABI.Errors {
+			if !bytes.Equal(data[:4], e.ID[:4]) {
+				continue
+			}
+			values, err := e.Unpack(data)
+			if err != nil {
+				continue
+			}
+			return e.Name + formatList(reflect.ValueOf(values), "(", ")")
+		}
+	}
+	return hexutil.Encode(data)
+}
+
+// TraceTransaction re-executes a mined transaction with the callTracer,
+// returning its outcome in the same form as a simulation.
+func TraceTransaction(ctx context.Context, client *rpc.Client, hash common.Hash) (*SimulationResult, error) {
+	config := map[string]interface{}{
+		"tracer":       "callTracer",
+		"tracerConfig": map[string]interface{}{"withLog": true},
+	}
+
+	frame := &callFrame{}
+	err := client.CallContext(ctx, frame, "debug_traceTransaction", hash, config)
+	if err != nil {
+		return nil, err
+	}
+	return frame.result(), nil
+}
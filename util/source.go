@@ -0,0 +1,109 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SourceFile is a single file of a verified contract's source.
+type SourceFile struct {
+	Path    string
+	Content string
+}
+
+type sourceEntry struct {
+	Content string `json:"content"`
+}
+
+type standardJSONInput struct {
+	Language string                 `json:"language"`
+	Sources  map[string]sourceEntry `json:"sources"`
+}
+
+// SourceFiles splits the verified source of a contract into its files.
+// Etherscan returns either a single flattened file, a json object of files,
+// or a standard json compiler input wrapped in an extra pair of braces.
+func (c *ContractData) SourceFiles() ([]SourceFile, error) {
+	src := strings.TrimSpace(c.SourceCode)
+	if !strings.HasPrefix(src, "{") {
+		name := c.ContractName
+		if name == "" {
+			name = "Contract"
+		}
+		return []SourceFile{{Path: name + ".sol", Content: c.SourceCode}}, nil
+	}
+
+	var sources map[string]sourceEntry
+	if strings.HasPrefix(src, "{{") {
+		input := &standardJSONInput{}
+		if err := json.Unmarshal([]byte(src[1:len(src)-1]), input); err != nil {
+			return nil, fmt.Errorf("failed to parse standard json input: %w", err)
+		}
+		sources = input.Sources
+	} else if err := json.Unmarshal([]byte(src), &sources); err != nil {
+		return nil, fmt.Errorf("failed to parse source files: %w", err)
+	}
+
+	files := make([]SourceFile, 0, len(sources))
+	for path, s := range sources {
+		files = append(files, SourceFile{Path: path, Content: s.Content})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// Definition is the location of a named declaration in the source.
+type Definition struct {
+	Kind string
+	Name string
+	File int
+	Line int
+}
+
+var definitionRe = regexp.MustCompile(`^\s*(?:abstract\s+)?(contract|interface|library|function|modifier|event|struct|enum|error)\s+([A-Za-z_$][A-Za-z0-9_$]*)`)
+
+// FindDefinitions returns the declarations of contracts, functions and
+// other named types across all files, ordered by file and line.
+func FindDefinitions(files []SourceFile) []Definition {
+	var defs []Definition
+	for i, f := range files {
+		for n, line := range strings.Split(f.Content, "\n") {
+			m := definitionRe.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			defs = append(defs, Definition{Kind: m[1], Name: m[2], File: i, Line: n})
+		}
+	}
+	return defs
+}
+
+// ExportSources writes the files to a new temporary directory, keeping
+// their relative paths, and returns the directory.
+func ExportSources(files []SourceFile, name string) (string, error) {
+	dir, err := os.MkdirTemp("", fmt.Sprintf("ethscan-%s-*", name))
+	if err != nil {
+		return "", err
+	}
+	for _, f := range files {
+		rel := filepath.Clean(filepath.FromSlash(f.Path))
+		// keep paths inside the export dir
+		rel = strings.TrimLeft(rel, string(filepath.Separator))
+		for strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			rel = strings.TrimPrefix(rel, ".."+string(filepath.Separator))
+		}
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return dir, err
+		}
+		if err := os.WriteFile(path, []byte(f.Content), 0644); err != nil {
+			return dir, err
+		}
+	}
+	return dir, nil
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSourceFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		contract ContractData
+		want     []SourceFile
+		wantErr  bool
+	}{
+		{
+			name:     "flattened",
+			contract: ContractData{ContractName: "Token", SourceCode: "pragma solidity ^0.8.0;\ncontract Token {}"},
+			want:     []SourceFile{{Path: "Token.sol", Content: "pragma solidity ^0.8.0;\ncontract Token {}"}},
+		},
+		{
+			name:     "flattened without a name",
+			contract: ContractData{SourceCode: "contract A {}"},
+			want:     []SourceFile{{Path: "Contract.sol", Content: "contract A {}"}},
+		},
+		{
+			name:     "json files",
+			contract: ContractData{SourceCode: `{"b/B.sol": {"content": "contract B {}"}, "A.sol": {"content": "contract A {}"}}`},
+			want:     []SourceFile{{Path: "A.sol", Content: "contract A {}"}, {Path: "b/B.sol", Content: "contract B {}"}},
+		},
+		{
+			name: "standard json input",
+			contract: ContractData{SourceCode: `{{
+  "language": "Solidity",
+  "sources": {"contracts/Token.sol": {"content": "import \"./IToken.sol\";"}, "contracts/IToken.sol": {"content": "interface IToken {}"}},
+  "settings": {"optimizer": {"enabled": true}}
+}}`},
+			want: []SourceFile{{Path: "contracts/IToken.sol", Content: "interface IToken {}"}, {Path: "contracts/Token.sol", Content: `import "./IToken.sol";`}},
+		},
+		{name: "malformed json", contract: ContractData{SourceCode: `{"A.sol": `}, wantErr: true},
+		{name: "malformed standard json", contract: ContractData{SourceCode: `{{"sources": }}`}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.contract.SourceFiles()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("SourceFiles = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SourceFiles: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SourceFiles = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindDefinitions(t *testing.T) {
+	files := []SourceFile{
+		{Path: "A.sol", Content: "// contract Commented\nabstract contract A {\n    event Transfer(address to);\n    function _move() internal {}\n}"},
+		{Path: "L.sol", Content: "library L {\n  error Failed();\n  struct Point { uint x; }\n}"},
+	}
+	want := []Definition{
+		{Kind: "contract", Name: "A", File: 0, Line: 1},
+		{Kind: "event", Name: "Transfer", File: 0, Line: 2},
+		{Kind: "function", Name: "_move", File: 0, Line: 3},
+		{Kind: "library", Name: "L", File: 1, Line: 0},
+		{Kind: "error", Name: "Failed", File: 1, Line: 1},
+		{Kind: "struct", Name: "Point", File: 1, Line: 2},
+	}
+	if got := FindDefinitions(files); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDefinitions = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,192 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// SourceMapEntry is one decompressed entry of a solidity source map,
+// corresponding to a single instruction.
+type SourceMapEntry struct {
+	Start    int
+	Length   int
+	File     int
+	Jump     string
+	Modifier int
+}
+
+// ParseSourceMap decompresses a solidity source map, where empty fields
+// inherit the value of the previous entry.
+func ParseSourceMap(sourceMap string) []SourceMapEntry {
+	var entries []SourceMapEntry
+	prev := SourceMapEntry{File: -1}
+	for _, item := range strings.Split(sourceMap, ";") {
+		e := prev
+		for i, field := range strings.Split(item, ":") {
+			if field == "" {
+				continue
+			}
+			switch i {
+			case 0:
+				e.Start, _ = strconv.Atoi(field)
+			case 1:
+				e.Length, _ = strconv.Atoi(field)
+			case 2:
+				e.File, _ = strconv.Atoi(field)
+			case 3:
+				e.Jump = field
+			case 4:
+				e.Modifier, _ = strconv.Atoi(field)
+			}
+		}
+		entries = append(entries, e)
+		prev = e
+	}
+	return entries
+}
+
+// SourceMapper maps program counters of a contract's runtime code to lines
+// of its verified source.
+type SourceMapper struct {
+	entries []SourceMapEntry
+	paths   map[int]string
+	files   map[string]string
+	pcIndex map[uint64]int
+}
+
+// Locate returns the source file and zero based line executing at pc.
+func (m *SourceMapper) Locate(pc uint64) (string, int, bool) {
+	idx, ok := m.pcIndex[pc]
+	if !ok || idx >= len(m.entries) {
+		return "", 0, false
+	}
+	e := m.entries[idx]
+	path, ok := m.paths[e.File]
+	if !ok {
+		return "", 0, false
+	}
+	content := m.files[path]
+	if e.Start > len(content) {
+		return "", 0, false
+	}
+	return path, strings.Count(content[:e.Start], "\n"), true
+}
+
+type solcOutput struct {
+	Errors []struct {
+		Severity         string `json:"severity"`
+		FormattedMessage string `json:"formattedMessage"`
+	} `json:"errors"`
+	Sources map[string]struct {
+		ID int `json:"id"`
+	} `json:"sources"`
+	Contracts map[string]map[string]struct {
+		EVM struct {
+			DeployedBytecode struct {
+				SourceMap string `json:"sourceMap"`
+			} `json:"deployedBytecode"`
+		} `json:"evm"`
+	} `json:"contracts"`
+}
+
+// solcVersion returns the version of the solc binary in PATH
+func solcVersion(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "solc", "--version").Output()
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "Version: ") {
+			return strings.TrimPrefix(line, "Version: "), nil
+		}
+	}
+	return "", fmt.Errorf("unexpected solc version output")
+}
+
+// NewSourceMapper recompiles the verified source with a local solc, which
+// must match the compiler version the contract was verified with, to obtain
+// the runtime source map. Etherscan does not serve source maps itself.
+func NewSourceMapper(ctx context.Context, c *ContractData, code []byte) (*SourceMapper, error) {
+	version, err := solcVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("solc not available: %w", err)
+	}
+	// etherscan reports versions like v0.8.17+commit.8df45f5f
+	want := strings.TrimPrefix(c.CompilerVersion, "v")
+	if !strings.HasPrefix(version, want) {
+		return nil, fmt.Errorf("solc %s does not match compiler %s", version, c.CompilerVersion)
+	}
+
+	files, err := c.SourceFiles()
+	if err != nil {
+		return nil, err
+	}
+	sources := make(map[string]interface{}, len(files))
+	contents := make(map[string]string, len(files))
+	for _, f := range files {
+		sources[f.Path] = map[string]string{"content": f.Content}
+		contents[f.Path] = f.Content
+	}
+	settings := map[string]interface{}{
+		"optimizer": map[string]interface{}{"enabled": c.OptimizationUsed, "runs": c.Runs},
+		"outputSelection": map[string]interface{}{
+			"*": map[string]interface{}{"*": []string{"evm.deployedBytecode.sourceMap"}},
+		},
+	}
+	if c.EVMVersion != "" && !strings.EqualFold(c.EVMVersion, "default") {
+		settings["evmVersion"] = c.EVMVersion
+	}
+	input, err := json.Marshal(map[string]interface{}{
+		"language": "Solidity",
+		"sources":  sources,
+		"settings": settings,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, "solc", "--standard-json")
+	cmd.Stdin = bytes.NewReader(input)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("solc failed: %w", err)
+	}
+	output := &solcOutput{}
+	if err := json.Unmarshal(out, output); err != nil {
+		return nil, err
+	}
+	for _, e := range output.Errors {
+		if e.Severity == "error" {
+			return nil, fmt.Errorf("compilation failed: %s", e.FormattedMessage)
+		}
+	}
+
+	var sourceMap string
+	for _, contracts := range output.Contracts {
+		if compiled, ok := contracts[c.ContractName]; ok {
+			sourceMap = compiled.EVM.DeployedBytecode.SourceMap
+		}
+	}
+	if sourceMap == "" {
+		return nil, fmt.Errorf("no source map for %s", c.ContractName)
+	}
+
+	m := &SourceMapper{
+		entries: ParseSourceMap(sourceMap),
+		paths:   make(map[int]string),
+		files:   contents,
+		pcIndex: make(map[uint64]int),
+	}
+	for path, s := range output.Sources {
+		m.paths[s.ID] = path
+	}
+	for i, instr := range Disassemble(code) {
+		m.pcIndex[instr.PC] = i
+	}
+	return m, nil
+}
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BlockStats summarizes the transactions of a block. Fields derived from
+// receipts only cover the transactions whose receipt is known.
+type BlockStats struct {
+	// Types counts the transactions by type
+	Types     map[uint8]int
+	Creations int
+	Failed    int
+	// Receipts is the number of receipts the stats were computed from
+	Receipts    int
+	Fees        Fees
+	BlobGasUsed uint64
+	// effective gas prices, nil without transactions
+	MinGasPrice    *big.Int
+	MedianGasPrice *big.Int
+	MaxGasPrice    *big.Int
+}
+
+// GasUsedPct returns the gas used by the block as a percentage of its limit
+func GasUsedPct(header *types.Header) float64 {
+	if header.GasLimit == 0 {
+		return 0
+	}
+	return float64(header.GasUsed) / float64(header.GasLimit) * 100
+}
+
+// GetBlockStats computes the stats of a block from the receipts of its
+// transactions which are known, looking up its rollup transactions in
+// rollups
+func GetBlockStats(block *types.Block, receipts map[common.Hash]*Receipt, rollups *RollupTxs) BlockStats {
+	s := BlockStats{Types: make(map[uint8]int)}
+	var prices []*big.Int
+	for _, txn := range block.Transactions() {
+		s.Types[rollups.TxType(txn)]++
+		if txn.To() == nil {
+			s.Creations++
+		}
+
+		rec, ok := receipts[rollups.TxHash(txn)]
+		if !ok || rec == nil {
+			prices = append(prices, EffectiveGasPrice(txn, block.BaseFee()))
+			continue
+		}
+		s.Receipts++
+		if rec.Status != types.ReceiptStatusSuccessful {
+			s.Failed++
+		}
+		s.BlobGasUsed += rec.BlobGasUsed
+		fees := GetFees(rec, txn, block.BaseFee())
+		s.Fees = s.Fees.Add(fees)
+		prices = append(prices, fees.GasPrice)
+	}
+
+	if len(prices) > 0 {
+		sort.Slice(prices, func(i, j int) bool { return prices[i].Cmp(prices[j]) < 0 })
+		s.MinGasPrice = prices[0]
+		s.MaxGasPrice = prices[len(prices)-1]
+		mid := len(prices) / 2
+		s.MedianGasPrice = prices[mid]
+		if len(prices)%2 == 0 {
+			s.MedianGasPrice = new(big.Int).Add(prices[mid-1], prices[mid])
+			s.MedianGasPrice.Rsh(s.MedianGasPrice, 1)
+		}
+	}
+	return s
+}
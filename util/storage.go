@@ -0,0 +1,114 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// NamedSlot is a well known storage slot.
+type NamedSlot struct {
+	Name string
+	Slot common.Hash
+}
+
+// EIP1967Slots are the proxy storage slots defined by EIP-1967.
+var EIP1967Slots = []NamedSlot{
+	{"implementation", common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")},
+	{"admin", common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")},
+	{"beacon", common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")},
+	{"rollback", common.HexToHash("0x4910fdfa16fed3260ed0e7147f7cc6da11a60208b5b9406d12a635614ffd9143")},
+}
+
+// ParseSlot parses a storage slot given as a decimal or hex number, or as
+// the name of an EIP-1967 slot.
+func ParseSlot(input string) (common.Hash, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	for _, s := range EIP1967Slots {
+		if input == s.Name {
+			return s.Slot, nil
+		}
+	}
+	num, ok := parseBigInt(input)
+	if !ok || num.Sign() < 0 || num.BitLen() > 256 {
+		return common.Hash{}, fmt.Errorf("invalid slot: %s", input)
+	}
+	return common.BigToHash(num), nil
+}
+
+// EncodeMappingKey encodes a mapping key the way solidity does when
+// computing the slot of a mapping value. The key type is inferred from the
+// input: addresses and numbers are left padded to 32 bytes, 32 byte hex
+// values are used as is and anything else is hashed as a string.
+func EncodeMappingKey(input string) []byte {
+	input = strings.TrimSpace(input)
+	if common.IsHexAddress(input) {
+		return common.LeftPadBytes(common.HexToAddress(input).Bytes(), 32)
+	}
+	if b, err := hexutil.Decode(input); err == nil && len(b) == 32 {
+		return b
+	}
+	if num, ok := parseBigInt(input); ok {
+		return math256(num)
+	}
+	return []byte(unquote(input))
+}
+
+// math256 returns the two's complement 32 byte encoding of num
+func math256(num *big.Int) []byte {
+	if num.Sign() < 0 {
+		num = new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 256), num)
+	}
+	return common.LeftPadBytes(num.Bytes(), 32)
+}
+
+// MappingSlot returns the slot of the value for key in a mapping stored at
+// slot, keccak256(key . slot).
+func MappingSlot(key []byte, slot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(key, slot.Bytes())
+}
+
+// ArraySlot returns the slot of the element at index of a dynamic array
+// stored at slot, keccak256(slot) + index.
+func ArraySlot(slot common.Hash, index *big.Int) common.Hash {
+	base := new(big.Int).SetBytes(crypto.Keccak256(slot.Bytes()))
+	sum := new(big.Int).Add(base, index)
+	return common.BytesToHash(math256(new(big.Int).Mod(sum, new(big.Int).Lsh(big.NewInt(1), 256))))
+}
+
+// GetStorageAt reads a storage slot of a contract at the given block.
+func GetStorageAt(ctx context.Context, client *rpc.Client, address common.Address, slot common.Hash, block string) (StorageValue, error) {
+	var result hexutil.Bytes
+	err := client.CallContext(ctx, &result, "eth_getStorageAt", address, slot, block)
+	if err != nil {
+		return StorageValue{}, err
+	}
+	return StorageValue(common.BytesToHash(result)), nil
+}
+
+// StorageValue is a raw 32 byte storage word.
+type StorageValue common.Hash
+
+func (v StorageValue) Bytes32() string {
+	return common.Hash(v).Hex()
+}
+
+func (v StorageValue) Uint() *big.Int {
+	return new(big.Int).SetBytes(v[:])
+}
+
+// Address interprets the lower 20 bytes of the word as an address.
+func (v StorageValue) Address() common.Address {
+	return common.BytesToAddress(v[12:])
+}
+
+// Bool interprets the lowest byte of the word as a bool.
+func (v StorageValue) Bool() bool {
+	return v[31] != 0
+}
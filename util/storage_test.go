@@ -0,0 +1,96 @@
+package util
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestParseSlot(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "0", want: "0x0000000000000000000000000000000000000000000000000000000000000000"},
+		{input: "10", want: "0x000000000000000000000000000000000000000000000000000000000000000a"},
+		{input: "0x10", want: "0x0000000000000000000000000000000000000000000000000000000000000010"},
+		{input: " Implementation ", want: "0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"},
+		{input: "-1"},
+		{input: "0x10000000000000000000000000000000000000000000000000000000000000000"},
+		{input: "owner"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSlot(tt.input)
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("ParseSlot(%q) = %s, want an error", tt.input, got.Hex())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSlot(%q): %v", tt.input, err)
+			}
+			if got.Hex() != tt.want {
+				t.Errorf("ParseSlot(%q) = %s, want %s", tt.input, got.Hex(), tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeMappingKey(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "0x000000000000000000000000000000000000dEaD", want: "0x000000000000000000000000000000000000000000000000000000000000dead"},
+		{input: "1", want: "0x0000000000000000000000000000000000000000000000000000000000000001"},
+		{input: "-1", want: "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"},
+		{input: "0x4a1c5b7c3b0f2e8d9c6b5a4f3e2d1c0b0a99887766554433221100ffeeddccbb", want: "0x4a1c5b7c3b0f2e8d9c6b5a4f3e2d1c0b0a99887766554433221100ffeeddccbb"},
+		{input: `"vitalik"`, want: hexutil.Encode([]byte("vitalik"))},
+		{input: "vitalik", want: hexutil.Encode([]byte("vitalik"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := hexutil.Encode(EncodeMappingKey(tt.input)); got != tt.want {
+				t.Errorf("EncodeMappingKey(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMappingSlot(t *testing.T) {
+	// keccak256 of 64 zero bytes
+	want := common.HexToHash("0xad3228b676f7d3cd4284a5443f17f1962b36e491b30a40b2405849e597ba5fb5")
+	if got := MappingSlot(EncodeMappingKey("0"), common.Hash{}); got != want {
+		t.Errorf("MappingSlot = %s, want %s", got.Hex(), want.Hex())
+	}
+}
+
+func TestArraySlot(t *testing.T) {
+	// keccak256 of 32 zero bytes
+	base := common.HexToHash("0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e563")
+	wrap := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), base.Big())
+
+	tests := []struct {
+		name  string
+		index *big.Int
+		want  common.Hash
+	}{
+		{name: "first", index: big.NewInt(0), want: base},
+		{name: "second", index: big.NewInt(1), want: common.HexToHash("0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e564")},
+		{name: "wraps around", index: new(big.Int).Add(wrap, big.NewInt(2)), want: common.BigToHash(big.NewInt(2))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ArraySlot(common.Hash{}, tt.index); got != tt.want {
+				t.Errorf("ArraySlot(%s) = %s, want %s", tt.index, got.Hex(), tt.want.Hex())
+			}
+		})
+	}
+}